@@ -0,0 +1,62 @@
+package calldata
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// verifyArguments are the arguments of Verify(bytes proof, uint256[] public_inputs)
+var verifyArguments abi.Arguments
+
+func init() {
+	bytesType, err := abi.NewType("bytes", "", nil)
+	if err != nil {
+		panic(err)
+	}
+	uint256ArrayType, err := abi.NewType("uint256[]", "", nil)
+	if err != nil {
+		panic(err)
+	}
+	verifyArguments = abi.Arguments{
+		{Name: "proof", Type: bytesType},
+		{Name: "public_inputs", Type: uint256ArrayType},
+	}
+}
+
+// DecodeVerifyCall decodes the calldata of a call to a function taking
+// (bytes proof, uint256[] public_inputs), such as test_verifier_go in
+// TestVerifier.sol. The first 4 bytes (the function selector) are skipped.
+func DecodeVerifyCall(data []byte) (proof []byte, publicInputs []*big.Int, err error) {
+	if len(data) < 4 {
+		return nil, nil, errors.New("calldata is shorter than a function selector")
+	}
+	values, err := verifyArguments.Unpack(data[4:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode calldata: %w", err)
+	}
+	proof, ok := values[0].([]byte)
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected type %T for the proof", values[0])
+	}
+	publicInputs, ok = values[1].([]*big.Int)
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected type %T for the public inputs", values[1])
+	}
+	return proof, publicInputs, nil
+}
+
+// EncodeVerifyCall encodes a call to method(bytes proof, uint256[] public_inputs),
+// such as test_verifier_go in TestVerifier.sol. It is the inverse of
+// DecodeVerifyCall.
+func EncodeVerifyCall(method string, proof []byte, publicInputs []*big.Int) ([]byte, error) {
+	args, err := verifyArguments.Pack(proof, publicInputs)
+	if err != nil {
+		return nil, fmt.Errorf("encode calldata: %w", err)
+	}
+	selector := crypto.Keccak256([]byte(method + "(bytes,uint256[])"))[:4]
+	return append(selector, args...), nil
+}
@@ -0,0 +1,51 @@
+package calldata
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestVerifyCallRoundTrip(t *testing.T) {
+
+	for _, n := range testNbCommitments {
+		proof := MarshalProof(randomProof(t, n))
+		for _, publicInputs := range [][]*big.Int{
+			{},
+			{big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 255)},
+		} {
+			data, err := EncodeVerifyCall("Verify", proof, publicInputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if selector := crypto.Keccak256([]byte("Verify(bytes,uint256[])"))[:4]; !bytes.Equal(data[:4], selector) {
+				t.Fatalf("selector %x, expected %x", data[:4], selector)
+			}
+
+			decodedProof, decodedPublicInputs, err := DecodeVerifyCall(data)
+			if err != nil {
+				t.Fatalf("%d commitments, %d public inputs: %v", n, len(publicInputs), err)
+			}
+			if !bytes.Equal(decodedProof, proof) {
+				t.Errorf("%d commitments, %d public inputs: the proof is not decoded", n, len(publicInputs))
+			}
+			if len(decodedPublicInputs) != len(publicInputs) {
+				t.Fatalf("%d commitments: %d public inputs decoded, expected %d", n, len(decodedPublicInputs), len(publicInputs))
+			}
+			for i := range publicInputs {
+				if decodedPublicInputs[i].Cmp(publicInputs[i]) != 0 {
+					t.Errorf("%d commitments: public input %d is %s, expected %s", n, i, decodedPublicInputs[i], publicInputs[i])
+				}
+			}
+
+			// truncated calldata
+			for _, size := range []int{0, 3, 4, 4 + 32, len(data) - 32, len(data) - 1} {
+				if _, _, err := DecodeVerifyCall(data[:size]); err == nil {
+					t.Errorf("%d commitments, %d public inputs: calldata truncated to %d bytes decoded", n, len(publicInputs), size)
+				}
+			}
+		}
+	}
+}
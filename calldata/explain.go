@@ -0,0 +1,184 @@
+package calldata
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+)
+
+// Annotation describes a word of the arguments of Verify.
+type Annotation struct {
+
+	// Name of the word, e.g. proof_l_com_x or public_inputs[3]
+	Name string
+
+	// Offset of the word in the bytes memory proof as used in Verifier.sol, so the
+	// first word of the proof is at 0x20. For a public input it is its index.
+	Offset int
+
+	// Value of the word, nil if the word is missing
+	Value *big.Int
+
+	// Issues lists what is wrong with the word, it is empty for a valid word
+	Issues []string
+}
+
+// Report is the annotated content of the arguments of Verify.
+type Report struct {
+	Proof        []Annotation
+	PublicInputs []Annotation
+
+	// Issues lists the problems which are not related to a single word
+	Issues []string
+}
+
+// HasIssues returns true if anything in the report is flagged.
+func (r *Report) HasIssues() bool {
+	if len(r.Issues) > 0 {
+		return true
+	}
+	for _, a := range r.Proof {
+		if len(a.Issues) > 0 {
+			return true
+		}
+	}
+	for _, a := range r.PublicInputs {
+		if len(a.Issues) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Annotate names every word of proof and publicInputs, as read by the Verifier.sol
+// generated from vk. The number of BSB22 commitments is taken from vk. Scalars
+// that are not reduced mod r, coordinates that are not reduced mod p and points
+// that are not on Bn254 are flagged.
+func Annotate(proof []byte, publicInputs []*big.Int, vk *bn254plonk.VerifyingKey) Report {
+
+	var report Report
+
+	nbCommitments := len(vk.CommitmentConstraintIndexes)
+	expectedSize := ProofSize(nbCommitments)
+	if len(proof) != expectedSize {
+		report.Issues = append(report.Issues, fmt.Sprintf("the proof is %d bytes long, the vk (%d BSB22 commitments) expects %d bytes", len(proof), nbCommitments, expectedSize))
+		if nb, err := NbCommitments(len(proof)); err == nil {
+			report.Issues = append(report.Issues, fmt.Sprintf("the size of the proof matches %d BSB22 commitments", nb))
+		}
+	}
+
+	fields := ProofLayout(nbCommitments)
+	for i := range fields {
+		words := fields[i].Words()
+		annotations := make([]Annotation, len(words))
+		for j, w := range words {
+			annotations[j] = Annotation{Name: w.Name, Offset: w.Offset + 0x20}
+			if w.Offset+fr.Bytes > len(proof) {
+				annotations[j].Issues = append(annotations[j].Issues, "missing")
+				continue
+			}
+			annotations[j].Value = new(big.Int).SetBytes(proof[w.Offset : w.Offset+fr.Bytes])
+			if w.Coordinate {
+				if annotations[j].Value.Cmp(fp.Modulus()) >= 0 {
+					annotations[j].Issues = append(annotations[j].Issues, "non canonical coordinate (≥ p_mod)")
+				}
+			} else if annotations[j].Value.Cmp(fr.Modulus()) >= 0 {
+				annotations[j].Issues = append(annotations[j].Issues, "non canonical scalar (≥ r_mod)")
+			}
+		}
+		if fields[i].Kind == Point && !isOnCurve(annotations[0].Value, annotations[1].Value) {
+			for j := range annotations {
+				annotations[j].Issues = append(annotations[j].Issues, "point not on Bn254")
+			}
+		}
+		report.Proof = append(report.Proof, annotations...)
+	}
+
+	// trailing words that the verifier doesn't read
+	for offset := expectedSize; offset < len(proof); offset += fr.Bytes {
+		end := offset + fr.Bytes
+		if end > len(proof) {
+			end = len(proof)
+		}
+		report.Proof = append(report.Proof, Annotation{
+			Name:   "<unexpected>",
+			Offset: offset + 0x20,
+			Value:  new(big.Int).SetBytes(proof[offset:end]),
+			Issues: []string{"not part of the proof"},
+		})
+	}
+
+	if len(publicInputs) != int(vk.NbPublicVariables) {
+		report.Issues = append(report.Issues, fmt.Sprintf("%d public inputs, the vk expects %d", len(publicInputs), vk.NbPublicVariables))
+	}
+	for i, v := range publicInputs {
+		a := Annotation{Name: fmt.Sprintf("public_inputs[%d]", i), Offset: i, Value: v}
+		if v.Cmp(fr.Modulus()) >= 0 {
+			a.Issues = append(a.Issues, "non canonical scalar (≥ r_mod)")
+		}
+		if i >= int(vk.NbPublicVariables) {
+			a.Issues = append(a.Issues, "unexpected public input")
+		}
+		report.PublicInputs = append(report.PublicInputs, a)
+	}
+
+	return report
+}
+
+// Explain writes the annotated words of proof and publicInputs to w, see Annotate.
+func Explain(w io.Writer, proof []byte, publicInputs []*big.Int, vk *bn254plonk.VerifyingKey) error {
+
+	report := Annotate(proof, publicInputs, vk)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "proof (%d bytes)\n", len(proof))
+	for _, a := range report.Proof {
+		fmt.Fprintf(tw, "%#x\t%s\t%s\t%s\n", a.Offset, a.Name, formatWord(a.Value), formatIssues(a.Issues))
+	}
+	fmt.Fprintf(tw, "\npublic inputs (%d)\n", len(publicInputs))
+	for _, a := range report.PublicInputs {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", a.Offset, a.Name, formatWord(a.Value), formatIssues(a.Issues))
+	}
+	if len(report.Issues) > 0 {
+		fmt.Fprintln(tw)
+		for _, issue := range report.Issues {
+			fmt.Fprintf(tw, "/!\\ %s\n", issue)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// isOnCurve returns true if (x, y) is on Bn254 or is (0,0), which the precompiles
+// interpret as the point at infinity. Missing or non canonical coordinates are
+// reported separately so they are not checked here.
+func isOnCurve(x, y *big.Int) bool {
+	if x == nil || y == nil || x.Cmp(fp.Modulus()) >= 0 || y.Cmp(fp.Modulus()) >= 0 {
+		return true
+	}
+	var p bn254.G1Affine
+	p.X.SetBigInt(x)
+	p.Y.SetBigInt(y)
+	return p.IsInfinity() || p.IsOnCurve()
+}
+
+func formatWord(v *big.Int) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("0x%064x", v)
+}
+
+func formatIssues(issues []string) string {
+	if len(issues) == 0 {
+		return ""
+	}
+	return "/!\\ " + strings.Join(issues, "; ")
+}
@@ -0,0 +1,150 @@
+package calldata
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+)
+
+// addToWord returns a copy of data where the 32 bytes word at offset is
+// increased by v. The result must fit on 32 bytes.
+func addToWord(data []byte, offset int, v *big.Int) []byte {
+	res := append([]byte{}, data...)
+	var w big.Int
+	w.SetBytes(data[offset:offset+32]).Add(&w, v).FillBytes(res[offset : offset+32])
+	return res
+}
+
+// flaggedWords returns the names of the words of the proof with an issue
+func flaggedWords(r *Report) []string {
+	var res []string
+	for _, a := range r.Proof {
+		if len(a.Issues) > 0 {
+			res = append(res, a.Name)
+		}
+	}
+	return res
+}
+
+// TestAnnotate tampers with a real proof one word or one point at a time, and
+// checks that Annotate flags exactly the words tampered with, and that Explain
+// prints them flagged.
+func TestAnnotate(t *testing.T) {
+
+	example, err := circuits.Get("com-fiat-shamir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setup, err := example.Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, pi, err := setup.Prove(example.Assignment())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := MarshalProof(*proof)
+	publicInputs := make([]*big.Int, len(pi))
+	for i := range pi {
+		publicInputs[i] = pi[i].BigInt(new(big.Int))
+	}
+	nbCommitments := len(proof.Bsb22Commitments)
+
+	if r := Annotate(data, publicInputs, setup.VK); r.HasIssues() {
+		t.Fatalf("the valid call is flagged: %v, %v", flaggedWords(&r), r.Issues)
+	}
+
+	type tampered struct {
+		name    string
+		proof   []byte
+		flagged []string
+	}
+	var calls []tampered
+	for _, w := range ProofWords(nbCommitments) {
+		if w.Coordinate {
+			// x+p in place of the coordinate x
+			calls = append(calls, tampered{w.Name + "+p", addToWord(data, w.Offset, fp.Modulus()), []string{w.Name}})
+		} else {
+			// s+r in place of the scalar s
+			calls = append(calls, tampered{w.Name + "+r", addToWord(data, w.Offset, fr.Modulus()), []string{w.Name}})
+		}
+	}
+	fields := ProofLayout(nbCommitments)
+	for i := range fields {
+		if fields[i].Kind != Point {
+			continue
+		}
+		// (x, y+1) is not on the curve, y+1 = p is replaced by 0
+		words := fields[i].Words()
+		y := words[1].Offset
+		off := addToWord(data, y, big.NewInt(1))
+		if new(big.Int).SetBytes(off[y:y+32]).Cmp(fp.Modulus()) == 0 {
+			copy(off[y:y+32], make([]byte, 32))
+		}
+		calls = append(calls, tampered{fields[i].Name + " off the curve", off, []string{words[0].Name, words[1].Name}})
+	}
+
+	for _, c := range calls {
+		r := Annotate(c.proof, publicInputs, setup.VK)
+		if !r.HasIssues() {
+			t.Errorf("%s: not flagged", c.name)
+			continue
+		}
+		if flagged := flaggedWords(&r); !reflect.DeepEqual(flagged, c.flagged) {
+			t.Errorf("%s: %v flagged, expected %v", c.name, flagged, c.flagged)
+		}
+		if len(r.Issues) != 0 {
+			t.Errorf("%s: unexpected issues %v", c.name, r.Issues)
+		}
+
+		var buf bytes.Buffer
+		if err := Explain(&buf, c.proof, publicInputs, setup.VK); err != nil {
+			t.Fatal(err)
+		}
+		nbFlagged := 0
+		for _, line := range strings.Split(buf.String(), "\n") {
+			if !strings.Contains(line, "/!\\") {
+				continue
+			}
+			nbFlagged++
+			if fields := strings.Fields(line); len(fields) < 2 || !contains(c.flagged, fields[1]) {
+				t.Errorf("%s: Explain flags %q", c.name, line)
+			}
+		}
+		if nbFlagged != len(c.flagged) {
+			t.Errorf("%s: Explain flags %d lines, expected %d", c.name, nbFlagged, len(c.flagged))
+		}
+	}
+
+	// a missing word and a public input not reduced mod r
+	if len(publicInputs) != 0 {
+		shifted := append([]*big.Int{}, publicInputs...)
+		shifted[0] = new(big.Int).Add(shifted[0], fr.Modulus())
+		r := Annotate(data[:len(data)-32], shifted, setup.VK)
+		last := ProofWords(nbCommitments)[len(data)/32-1].Name
+		if flagged := flaggedWords(&r); !reflect.DeepEqual(flagged, []string{last}) {
+			t.Errorf("truncated proof: %v flagged, expected %s", flagged, last)
+		}
+		if len(r.Issues) == 0 {
+			t.Error("truncated proof: the size is not flagged")
+		}
+		if len(r.PublicInputs[0].Issues) == 0 {
+			t.Error("public input x+r is not flagged")
+		}
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package calldata
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+)
+
+// Kind is the type of a field of the proof.
+type Kind uint8
+
+const (
+	// Scalar is an element of Fr, serialised on one word
+	Scalar Kind = iota
+
+	// Point is a point on Bn254(Fp), serialised on two words x||y
+	Point
+)
+
+// Field describes an element of the serialised proof. The proof layout is the single
+// source of truth for the proof_* constants of Verifier.sol, the Proof struct of
+// TestVerifier.sol and the Go (de)serialisation.
+type Field struct {
+
+	// Name of the field. The Solidity names are proof_<Name> for a scalar and
+	// proof_<Name>_x, proof_<Name>_y for a point. Fields related to the commit api
+	// are suffixed with the index of the commitment.
+	Name string
+
+	Kind Kind
+
+	// Index of the BSB22 commitment the field relates to, -1 if the field is
+	// present in every proof.
+	Index int
+
+	// Offset of the field in the serialised proof, in bytes.
+	Offset int
+
+	// Source is the path of the field in bn254plonk.Proof
+	Source string
+
+	// Comment is emitted above the corresponding constants in Verifier.sol
+	Comment string
+
+	scalar func(*bn254plonk.Proof) *fr.Element
+	point  func(*bn254plonk.Proof) *bn254.G1Affine
+}
+
+// Word is a 32 bytes word of the serialised proof.
+type Word struct {
+
+	// Name of the word in Solidity, e.g. proof_l_com_x
+	Name string
+
+	// Offset of the word in the serialised proof, in bytes.
+	Offset int
+
+	// Coordinate is true if the word is a coordinate of a point (then it lives in
+	// Fp), false if it's a scalar (then it lives in Fr).
+	Coordinate bool
+
+	Field *Field
+}
+
+// ProofLayout returns the fields of a proof containing nbCommitments BSB22
+// commitments, in the order in which they are serialised.
+func ProofLayout(nbCommitments int) []Field {
+
+	var l layout
+
+	for i, name := range []string{"l_com", "r_com", "o_com"} {
+		i := i
+		l.point(name, fmt.Sprintf("LRO[%d]", i), func(p *bn254plonk.Proof) *bn254.G1Affine { return &p.LRO[i] })
+	}
+
+	for i := 0; i < 3; i++ {
+		i := i
+		h := l.point(fmt.Sprintf("h_%d", i), fmt.Sprintf("H[%d]", i), func(p *bn254plonk.Proof) *bn254.G1Affine { return &p.H[i] })
+		if i == 0 {
+			h.Comment = "h = h_0 + x^{n+2}h_1 + x^{2(n+2)}h_2"
+		}
+	}
+
+	l.claimedValue("l_at_zeta", 2).Comment = "wire values at zeta"
+	l.claimedValue("r_at_zeta", 3)
+	l.claimedValue("o_at_zeta", 4)
+	l.claimedValue("s1_at_zeta", 5).Comment = "Sσ1(zeta)"
+	l.claimedValue("s2_at_zeta", 6).Comment = "Sσ2(zeta)"
+
+	l.point("grand_product_commitment", "Z", func(p *bn254plonk.Proof) *bn254.G1Affine { return &p.Z }).Comment = "[z(x)]"
+	l.scalar("grand_product_at_zeta_omega", "ZShiftedOpening.ClaimedValue", func(p *bn254plonk.Proof) *fr.Element { return &p.ZShiftedOpening.ClaimedValue }).Comment = "z(w*zeta)"
+	l.claimedValue("quotient_polynomial_at_zeta", 0).Comment = "t(zeta)"
+	l.claimedValue("linearised_polynomial_at_zeta", 1).Comment = "r(zeta)"
+
+	l.point("batch_opening_at_zeta", "BatchedProof.H", func(p *bn254plonk.Proof) *bn254.G1Affine { return &p.BatchedProof.H }).Comment = "Folded proof for the opening of H, linearised poly, l, r, o, s_1, s_2, qcp: [Wzeta]"
+	l.point("opening_at_zeta_omega", "ZShiftedOpening.H", func(p *bn254plonk.Proof) *bn254.G1Affine { return &p.ZShiftedOpening.H }).Comment = "[Wzeta*omega]"
+
+	// [ openings_selector_commits || commitments_wires_commit_api]
+	for i := 0; i < nbCommitments; i++ {
+		l.claimedValue("openings_selector_commit_api_at_zeta", nbClaimedValues+i).Index = i
+	}
+	for i := 0; i < nbCommitments; i++ {
+		i := i
+		l.point("commitments_wires_commit_api", fmt.Sprintf("Bsb22Commitments[%d]", i), func(p *bn254plonk.Proof) *bn254.G1Affine { return &p.Bsb22Commitments[i] }).Index = i
+	}
+
+	return l.fields
+}
+
+// Size returns the size in bytes of the serialised field.
+func (f *Field) Size() int {
+	if f.Kind == Point {
+		return sizeG1
+	}
+	return sizeFr
+}
+
+// Scalar returns a pointer to the scalar described by f in proof. It panics
+// if f is not a Scalar.
+func (f *Field) Scalar(proof *bn254plonk.Proof) *fr.Element {
+	if f.Kind != Scalar {
+		panic("field " + f.Name + " is not a scalar")
+	}
+	return f.scalar(proof)
+}
+
+// Point returns a pointer to the point described by f in proof. It panics
+// if f is not a Point.
+func (f *Field) Point(proof *bn254plonk.Proof) *bn254.G1Affine {
+	if f.Kind != Point {
+		panic("field " + f.Name + " is not a point")
+	}
+	return f.point(proof)
+}
+
+// Words returns the words composing the serialised field.
+func (f *Field) Words() []Word {
+	name := "proof_" + f.Name
+	if f.Index >= 0 {
+		name = fmt.Sprintf("%s_%d", name, f.Index)
+	}
+	if f.Kind == Scalar {
+		return []Word{{Name: name, Offset: f.Offset, Field: f}}
+	}
+	return []Word{
+		{Name: name + "_x", Offset: f.Offset, Coordinate: true, Field: f},
+		{Name: name + "_y", Offset: f.Offset + fr.Bytes, Coordinate: true, Field: f},
+	}
+}
+
+// ProofWords returns all the words of a proof containing nbCommitments BSB22
+// commitments, in the order in which they are serialised.
+func ProofWords(nbCommitments int) []Word {
+	fields := ProofLayout(nbCommitments)
+	res := make([]Word, 0, ProofSize(nbCommitments)/fr.Bytes)
+	for i := range fields {
+		res = append(res, fields[i].Words()...)
+	}
+	return res
+}
+
+// layout appends fields at consecutive offsets. The returned pointers are
+// only valid until the next field is added.
+type layout struct {
+	fields []Field
+	offset int
+}
+
+func (l *layout) scalar(name, source string, get func(*bn254plonk.Proof) *fr.Element) *Field {
+	l.fields = append(l.fields, Field{Name: name, Kind: Scalar, Index: -1, Offset: l.offset, Source: source, scalar: get})
+	l.offset += sizeFr
+	return &l.fields[len(l.fields)-1]
+}
+
+func (l *layout) point(name, source string, get func(*bn254plonk.Proof) *bn254.G1Affine) *Field {
+	l.fields = append(l.fields, Field{Name: name, Kind: Point, Index: -1, Offset: l.offset, Source: source, point: get})
+	l.offset += sizeG1
+	return &l.fields[len(l.fields)-1]
+}
+
+// claimedValue adds the i-th claimed value of the batched opening at ζ
+func (l *layout) claimedValue(name string, i int) *Field {
+	return l.scalar(name, fmt.Sprintf("BatchedProof.ClaimedValues[%d]", i), func(p *bn254plonk.Proof) *fr.Element { return &p.BatchedProof.ClaimedValues[i] })
+}
@@ -0,0 +1,172 @@
+// Package calldata converts gnark PLONK proofs from and to the byte layout
+// expected by the Solidity verifier (the proof_* offsets in Verifier.sol).
+package calldata
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+)
+
+const (
+	// sizeFr is the size in bytes of a serialised scalar
+	sizeFr = fr.Bytes
+
+	// sizeG1 is the size in bytes of a serialised point on Bn254(Fp), x||y
+	sizeG1 = 2 * fp.Bytes
+
+	// nbClaimedValues is the number of claimed values in the batched opening
+	// at ζ, without the ones related to the commit api: H, linearised
+	// polynomial, L, R, O, S₁, S₂
+	nbClaimedValues = 7
+
+	// ProofSizeNoCommitment is the size in bytes of a proof without BSB22
+	// commitments: [L], [R], [O], [H₀], [H₁], [H₂] (12 words), L(ζ), R(ζ), O(ζ),
+	// S₁(ζ), S₂(ζ) (5 words), [Z], Z(ωζ), H(ζ), Linearised_polynomial(ζ) (5 words),
+	// [Wζ], [Wζω] (4 words).
+	ProofSizeNoCommitment = 26 * sizeFr
+
+	// ProofSizePerCommitment is the number of bytes each BSB22 commitment adds to the
+	// proof: the opening of the selector at ζ, followed (later in the proof) by the
+	// commitment to the wires.
+	ProofSizePerCommitment = sizeFr + sizeG1
+)
+
+var (
+	ErrProofSize       = errors.New("invalid proof size")
+	ErrNonCanonical    = errors.New("non canonical encoding")
+	ErrPointNotOnCurve = errors.New("point not on curve")
+)
+
+// ProofSize returns the size in bytes of a serialised proof containing
+// nbCommitments BSB22 commitments.
+func ProofSize(nbCommitments int) int {
+	return ProofSizeNoCommitment + nbCommitments*ProofSizePerCommitment
+}
+
+// NbCommitments returns the number of BSB22 commitments contained in a serialised
+// proof of size proofSize.
+func NbCommitments(proofSize int) (int, error) {
+	if proofSize < ProofSizeNoCommitment || (proofSize-ProofSizeNoCommitment)%ProofSizePerCommitment != 0 {
+		return 0, fmt.Errorf("%w: %d bytes", ErrProofSize, proofSize)
+	}
+	return (proofSize - ProofSizeNoCommitment) / ProofSizePerCommitment, nil
+}
+
+// MarshalProof serialises proof the way Verifier.sol reads it, following
+// ProofLayout. The result is the content of the bytes memory proof argument
+// of Verify.
+func MarshalProof(proof bn254plonk.Proof) []byte {
+
+	fields := ProofLayout(len(proof.Bsb22Commitments))
+	res := make([]byte, 0, ProofSize(len(proof.Bsb22Commitments)))
+
+	for i := range fields {
+		switch fields[i].Kind {
+		case Scalar:
+			tmp32 := fields[i].Scalar(&proof).Bytes()
+			res = append(res, tmp32[:]...)
+		case Point:
+			tmp64 := fields[i].Point(&proof).RawBytes()
+			res = append(res, tmp64[:]...)
+		}
+	}
+
+	return res
+}
+
+// UnmarshalProof is the inverse of MarshalProof. The number of BSB22 commitments
+// is deduced from the size of data. Every scalar and coordinate must be
+// canonical (i.e. reduced) and every point must be on Bn254, or be (0,0)
+// which stands for the point at infinity.
+func UnmarshalProof(data []byte) (bn254plonk.Proof, error) {
+	return unmarshalProof(data, false)
+}
+
+// ReduceProof is UnmarshalProof without the checks of the words: every scalar is
+// reduced mod r and every coordinate mod p, as fr.Element.SetBytes and
+// fp.Element.SetBytes do, and a point may be off the curve. It returns the proof
+// gnark's verifier sees when it is fed the raw words, e.g. to compare it with
+// Verifier.sol, which rejects them.
+func ReduceProof(data []byte) (bn254plonk.Proof, error) {
+	return unmarshalProof(data, true)
+}
+
+func unmarshalProof(data []byte, reduce bool) (bn254plonk.Proof, error) {
+
+	var proof bn254plonk.Proof
+
+	nbCommitments, err := NbCommitments(len(data))
+	if err != nil {
+		return proof, err
+	}
+	proof.BatchedProof.ClaimedValues = make([]fr.Element, nbClaimedValues+nbCommitments)
+	proof.Bsb22Commitments = make([]bn254.G1Affine, nbCommitments)
+
+	d := decoder{data: data, reduce: reduce}
+	fields := ProofLayout(nbCommitments)
+	for i := range fields {
+		switch fields[i].Kind {
+		case Scalar:
+			d.readFr(fields[i].Scalar(&proof))
+		case Point:
+			d.readG1(fields[i].Point(&proof))
+		}
+	}
+
+	return proof, d.err
+}
+
+// decoder reads consecutive words from data, it stops at the first error
+// and records it. If reduce is set, the words are reduced and never checked.
+type decoder struct {
+	data   []byte
+	offset int
+	err    error
+	reduce bool
+}
+
+func (d *decoder) readFr(z *fr.Element) {
+	if d.err != nil {
+		return
+	}
+	if d.reduce {
+		z.SetBytes(d.data[d.offset : d.offset+sizeFr])
+		d.offset += sizeFr
+		return
+	}
+	if err := z.SetBytesCanonical(d.data[d.offset : d.offset+sizeFr]); err != nil {
+		d.err = fmt.Errorf("%w: scalar at offset %#x", ErrNonCanonical, d.offset)
+		return
+	}
+	d.offset += sizeFr
+}
+
+func (d *decoder) readG1(p *bn254.G1Affine) {
+	if d.err != nil {
+		return
+	}
+	if d.reduce {
+		p.X.SetBytes(d.data[d.offset : d.offset+fp.Bytes])
+		p.Y.SetBytes(d.data[d.offset+fp.Bytes : d.offset+sizeG1])
+		d.offset += sizeG1
+		return
+	}
+	if err := p.X.SetBytesCanonical(d.data[d.offset : d.offset+fp.Bytes]); err != nil {
+		d.err = fmt.Errorf("%w: x coordinate at offset %#x", ErrNonCanonical, d.offset)
+		return
+	}
+	if err := p.Y.SetBytesCanonical(d.data[d.offset+fp.Bytes : d.offset+sizeG1]); err != nil {
+		d.err = fmt.Errorf("%w: y coordinate at offset %#x", ErrNonCanonical, d.offset+fp.Bytes)
+		return
+	}
+	if !p.IsInfinity() && !p.IsOnCurve() {
+		d.err = fmt.Errorf("%w: point at offset %#x", ErrPointNotOnCurve, d.offset)
+		return
+	}
+	d.offset += sizeG1
+}
@@ -0,0 +1,234 @@
+package calldata
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+)
+
+var testNbCommitments = []int{0, 1, 3, 8}
+
+// randomPoint returns [s]G for a random s
+func randomPoint(t *testing.T) bn254.G1Affine {
+	t.Helper()
+	var s fr.Element
+	if _, err := s.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	_, _, g, _ := bn254.Generators()
+	var res bn254.G1Affine
+	res.ScalarMultiplication(&g, s.BigInt(new(big.Int)))
+	return res
+}
+
+// randomProof returns a proof with nbCommitments BSB22 commitments whose fields
+// are random, it doesn't verify
+func randomProof(t *testing.T, nbCommitments int) bn254plonk.Proof {
+	t.Helper()
+
+	var proof bn254plonk.Proof
+	proof.BatchedProof.ClaimedValues = make([]fr.Element, nbClaimedValues+nbCommitments)
+	proof.Bsb22Commitments = make([]bn254.G1Affine, nbCommitments)
+	fields := ProofLayout(nbCommitments)
+	for i := range fields {
+		switch fields[i].Kind {
+		case Scalar:
+			if _, err := fields[i].Scalar(&proof).SetRandom(); err != nil {
+				t.Fatal(err)
+			}
+		case Point:
+			*fields[i].Point(&proof) = randomPoint(t)
+		}
+	}
+	return proof
+}
+
+// randomData returns a serialised proof with nbCommitments BSB22 commitments,
+// written word by word without MarshalProof. The last point is (0,0), the point
+// at infinity.
+func randomData(t *testing.T, nbCommitments int) []byte {
+	t.Helper()
+
+	var res []byte
+	fields := ProofLayout(nbCommitments)
+	for i := range fields {
+		switch fields[i].Kind {
+		case Scalar:
+			var s fr.Element
+			if _, err := s.SetRandom(); err != nil {
+				t.Fatal(err)
+			}
+			b := s.Bytes()
+			res = append(res, b[:]...)
+		case Point:
+			p := randomPoint(t)
+			x, y := p.X.Bytes(), p.Y.Bytes()
+			res = append(res, x[:]...)
+			res = append(res, y[:]...)
+		}
+	}
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Kind == Point {
+			copy(res[fields[i].Offset:fields[i].Offset+sizeG1], make([]byte, sizeG1))
+			break
+		}
+	}
+	return res
+}
+
+func TestProofRoundTrip(t *testing.T) {
+
+	for _, n := range testNbCommitments {
+
+		// Unmarshal(Marshal(p)) == p
+		proof := randomProof(t, n)
+		data := MarshalProof(proof)
+		if len(data) != ProofSize(n) {
+			t.Fatalf("%d commitments: %d bytes, expected %d", n, len(data), ProofSize(n))
+		}
+		decoded, err := UnmarshalProof(data)
+		if err != nil {
+			t.Fatalf("%d commitments: %v", n, err)
+		}
+		if !reflect.DeepEqual(decoded, proof) {
+			t.Fatalf("%d commitments: Unmarshal(Marshal(p)) != p", n)
+		}
+
+		// Marshal(Unmarshal(b)) == b
+		data = randomData(t, n)
+		proof, err = UnmarshalProof(data)
+		if err != nil {
+			t.Fatalf("%d commitments: %v", n, err)
+		}
+		if len(proof.Bsb22Commitments) != n {
+			t.Fatalf("%d commitments decoded, expected %d", len(proof.Bsb22Commitments), n)
+		}
+		if !bytes.Equal(MarshalProof(proof), data) {
+			t.Fatalf("%d commitments: Marshal(Unmarshal(b)) != b", n)
+		}
+	}
+}
+
+func TestNbCommitments(t *testing.T) {
+
+	for _, n := range testNbCommitments {
+		nb, err := NbCommitments(ProofSize(n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nb != n {
+			t.Fatalf("%d commitments, expected %d", nb, n)
+		}
+	}
+
+	for _, size := range []int{
+		0,
+		ProofSizeNoCommitment - sizeFr,
+		ProofSizeNoCommitment + sizeFr,
+		ProofSize(1) + 1,
+		ProofSize(3) - sizeG1,
+	} {
+		if _, err := NbCommitments(size); !errors.Is(err, ErrProofSize) {
+			t.Errorf("%d bytes: expected ErrProofSize, got %v", size, err)
+		}
+	}
+}
+
+func TestUnmarshalProofErrors(t *testing.T) {
+
+	const n = 1
+	data := MarshalProof(randomProof(t, n))
+	fields := ProofLayout(n)
+
+	// fieldNamed returns the offset of the field called name
+	fieldNamed := func(name string) int {
+		for i := range fields {
+			if fields[i].Name == name {
+				return fields[i].Offset
+			}
+		}
+		t.Fatalf("no field %s", name)
+		return 0
+	}
+	// with returns a copy of data where the word at offset is v
+	with := func(offset int, v *big.Int) []byte {
+		res := append([]byte{}, data...)
+		v.FillBytes(res[offset : offset+32])
+		return res
+	}
+	l := fieldNamed("l_com")
+	lAtZeta := fieldNamed("l_at_zeta")
+	var y big.Int
+	y.SetBytes(data[l+fp.Bytes:l+sizeG1]).Add(&y, big.NewInt(1)).Mod(&y, fp.Modulus())
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected error
+	}{
+		{"truncated by a word", data[:len(data)-sizeFr], ErrProofSize},
+		{"truncated by a byte", data[:len(data)-1], ErrProofSize},
+		{"empty", nil, ErrProofSize},
+		{"scalar equal to r", with(lAtZeta, fr.Modulus()), ErrNonCanonical},
+		{"x coordinate equal to p", with(l, fp.Modulus()), ErrNonCanonical},
+		{"y coordinate above p", with(l+fp.Bytes, new(big.Int).Add(fp.Modulus(), big.NewInt(1))), ErrNonCanonical},
+		{"point off the curve", with(l+fp.Bytes, &y), ErrPointNotOnCurve},
+	}
+	for _, tt := range tests {
+		if _, err := UnmarshalProof(tt.data); !errors.Is(err, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+		}
+	}
+}
+
+func TestReduceProof(t *testing.T) {
+
+	const n = 2
+	proof := randomProof(t, n)
+	data := MarshalProof(proof)
+
+	// every word is increased by its modulus, and still fits on 32 bytes
+	shifted := append([]byte{}, data...)
+	for _, w := range ProofWords(n) {
+		modulus := fr.Modulus()
+		if w.Coordinate {
+			modulus = fp.Modulus()
+		}
+		word := shifted[w.Offset : w.Offset+32]
+		new(big.Int).Add(new(big.Int).SetBytes(word), modulus).FillBytes(word)
+	}
+	if _, err := UnmarshalProof(shifted); !errors.Is(err, ErrNonCanonical) {
+		t.Fatalf("the shifted proof is decoded: %v", err)
+	}
+	reduced, err := ReduceProof(shifted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reduced, proof) {
+		t.Error("the shifted proof doesn't reduce to the proof")
+	}
+
+	// a point off the curve is kept as is
+	l := ProofLayout(n)[0]
+	data[l.Offset+63] ^= 1
+	if _, err := UnmarshalProof(data); !errors.Is(err, ErrPointNotOnCurve) {
+		t.Fatalf("the point off the curve is decoded: %v", err)
+	}
+	if reduced, err = ReduceProof(data); err != nil {
+		t.Fatal(err)
+	}
+	if p := l.Point(&reduced); p.IsOnCurve() || !bytes.Equal(MarshalProof(reduced), data) {
+		t.Error("the point off the curve is not kept")
+	}
+
+	if _, err := ReduceProof(data[:len(data)-1]); !errors.Is(err, ErrProofSize) {
+		t.Errorf("truncated proof: expected ErrProofSize, got %v", err)
+	}
+}
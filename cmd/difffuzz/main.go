@@ -0,0 +1,554 @@
+// Command difffuzz compares gnark's plonk.Verify with Verify of Verifier.sol on
+// mutated proofs.
+//
+// Each proof is generated from a random witness of an example circuit, then it is
+// mutated: a bit is flipped, r is added to a scalar or p to a coordinate, a point
+// is negated, or two points (e.g. the commitments L and R) are swapped, in the
+// proof or in the public inputs. Both verifiers must accept the genuine proof,
+// and must agree on every mutated one. Each disagreement is written to the corpus
+// directory, and the cases already in the corpus are replayed first.
+//
+// gnark is fed the raw words, reduced mod r or p as gnark reduces them, without
+// the checks of Verifier.sol: a point off the curve reaches gnark's verifier. A
+// word which is not reduced is the one disagreement expected, Verifier.sol
+// rejects it, but it must then accept the call with the words reduced.
+//
+// FuzzVerify runs the same comparison with go test's fuzzing engine, on one
+// circuit and from the genuine proof and the corpus:
+//
+//	go test ./cmd/difffuzz -fuzz FuzzVerify
+//
+// Every example circuit is fuzzed, or only -circuit. The Solidity verifier is
+// generated as a contract from the same verifying key as gnark's, compiled with
+// the local solc, and its Verify is called on an in-memory EVM (package evmrun),
+// each worker has its own copy.
+//
+// Usage:
+//
+//	go run ./cmd/difffuzz [-circuit com-fiat-shamir] [-proofs 16] [-mutations 64] [-workers 8] [-seed 1] [-corpus cmd/difffuzz/testdata] [-solc solc]
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/evmrun"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/reverts"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testCase is a proof and its public inputs, as passed to Verify. It is
+// the format of the files of the corpus.
+type testCase struct {
+	Circuit      string         `json:"circuit"`
+	Mutation     string         `json:"mutation"`
+	Proof        hexutil.Bytes  `json:"proof"`
+	PublicInputs []*hexutil.Big `json:"public_inputs"`
+
+	// Gnark and Solidity are the outcomes of both verifiers, "accepted" or the
+	// error
+	Gnark    string `json:"gnark"`
+	Solidity string `json:"solidity"`
+}
+
+func (tc *testCase) publicInputs() []*big.Int {
+	res := make([]*big.Int, len(tc.PublicInputs))
+	for i := range tc.PublicInputs {
+		res[i] = tc.PublicInputs[i].ToInt()
+	}
+	return res
+}
+
+func newTestCase(circuit, mutation string, proof []byte, publicInputs []*big.Int) *testCase {
+	tc := &testCase{
+		Circuit:      circuit,
+		Mutation:     mutation,
+		Proof:        proof,
+		PublicInputs: make([]*hexutil.Big, len(publicInputs)),
+	}
+	for i := range publicInputs {
+		tc.PublicInputs[i] = (*hexutil.Big)(publicInputs[i])
+	}
+	return tc
+}
+
+// outcome formats the result of a verifier
+func outcome(err error) string {
+	if err == nil {
+		return "accepted"
+	}
+	return err.Error()
+}
+
+// verifyGnark runs gnark's verifier on the raw words of the proof and on the
+// public inputs, reduced as gnark reduces them (calldata.ReduceProof, and
+// fr.Element.SetBigInt), without the checks of Verifier.sol: gnark alone must
+// reject a point off the curve. A proof of a size gnark can't represent is
+// rejected, as well as a proof making the verifier panic.
+func verifyGnark(vk *bn254plonk.VerifyingKey, proofBytes []byte, publicInputs []*big.Int) (err error) {
+
+	proof, err := calldata.ReduceProof(proofBytes)
+	if err != nil {
+		return err
+	}
+	publicWitness := make(fr.Vector, len(publicInputs))
+	for i := range publicInputs {
+		publicWitness[i].SetBigInt(publicInputs[i])
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return bn254plonk.Verify(&proof, vk, publicWitness)
+}
+
+// verifySolidity calls Verify on the verifier deployed by r
+func verifySolidity(r *evmrun.Runner, proof []byte, publicInputs []*big.Int) error {
+	res, err := r.Verify("Verify", proof, publicInputs)
+	if err != nil {
+		return err
+	}
+	return res.Err
+}
+
+// nonCanonical returns true if err is a rejection by Verifier.sol of a scalar, a
+// coordinate or a public input which is not reduced, which gnark reduces
+func nonCanonical(err error) bool {
+	var verr *reverts.VerifierError
+	if !errors.As(err, &verr) {
+		return false
+	}
+	switch verr.Spec.Name {
+	case "PublicInputNotReduced", "ProofScalarNotReduced", "ProofCoordinateNotReduced":
+		return true
+	}
+	return false
+}
+
+// reduce returns a copy of a well sized proof and of publicInputs, where every
+// scalar and public input is reduced mod r and every coordinate mod p
+func reduce(proof []byte, publicInputs []*big.Int) ([]byte, []*big.Int) {
+	nbCommitments, err := calldata.NbCommitments(len(proof))
+	if err != nil {
+		panic(err)
+	}
+	p := make([]byte, len(proof))
+	for _, w := range calldata.ProofWords(nbCommitments) {
+		modulus := fr.Modulus()
+		if w.Coordinate {
+			modulus = fp.Modulus()
+		}
+		word := new(big.Int).SetBytes(proof[w.Offset : w.Offset+32])
+		word.Mod(word, modulus).FillBytes(p[w.Offset : w.Offset+32])
+	}
+	pi := make([]*big.Int, len(publicInputs))
+	for i := range publicInputs {
+		pi[i] = new(big.Int).Mod(publicInputs[i], fr.Modulus())
+	}
+	return p, pi
+}
+
+// compare runs both verifiers on tc and fills its outcomes. The verifiers agree
+// if both accept or both reject; or if gnark accepts words which Verifier.sol
+// rejects because they are not reduced, and Verifier.sol accepts them reduced.
+func compare(vk *bn254plonk.VerifyingKey, r *evmrun.Runner, tc *testCase) (agree bool) {
+
+	publicInputs := tc.publicInputs()
+	errGnark := verifyGnark(vk, tc.Proof, publicInputs)
+	errSolidity := verifySolidity(r, tc.Proof, publicInputs)
+	tc.Gnark, tc.Solidity = outcome(errGnark), outcome(errSolidity)
+
+	if (errGnark == nil) == (errSolidity == nil) {
+		return true
+	}
+	if errGnark == nil && nonCanonical(errSolidity) {
+		proof, publicInputs := reduce(tc.Proof, publicInputs)
+		if err := verifySolidity(r, proof, publicInputs); err != nil {
+			tc.Solidity += ", reduced: " + outcome(err)
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// mutation alters a copy of a proof and of its public inputs, and describes the
+// change. ok is false if the mutation doesn't apply, e.g. there is no point
+// to negate.
+type mutation func(rng *rand.Rand, proof []byte, publicInputs []*big.Int) (description string, ok bool)
+
+var mutations = []mutation{flipBit, addModulus, negatePoint, swapPoints}
+
+// flipBit flips a random bit of the proof or of a public input
+func flipBit(rng *rand.Rand, proof []byte, publicInputs []*big.Int) (string, bool) {
+	i := rng.Intn(len(proof)*8 + len(publicInputs)*256)
+	if i < len(proof)*8 {
+		proof[i/8] ^= 1 << (i % 8)
+		field := fieldAt(proof, i/8)
+		return fmt.Sprintf("flip bit %d of byte %d (%s)", i%8, i/8, field.Name), true
+	}
+	i -= len(proof) * 8
+	publicInputs[i/256].SetBit(publicInputs[i/256], i%256, publicInputs[i/256].Bit(i%256)^1)
+	return fmt.Sprintf("flip bit %d of public input %d", i%256, i/256), true
+}
+
+// addModulus adds r to a scalar of the proof or to a public input, or p to a
+// coordinate of a point, which yields the same element of Fr or Fp in a non
+// canonical form
+func addModulus(rng *rand.Rand, proof []byte, publicInputs []*big.Int) (string, bool) {
+	nbCommitments, err := calldata.NbCommitments(len(proof))
+	if err != nil {
+		panic(err)
+	}
+	words := calldata.ProofWords(nbCommitments)
+	i := rng.Intn(len(words) + len(publicInputs))
+	if i < len(words) {
+		w, modulus, name := words[i], fr.Modulus(), "r"
+		if w.Coordinate {
+			modulus, name = fp.Modulus(), "p"
+		}
+		word := proof[w.Offset : w.Offset+32]
+		new(big.Int).Add(new(big.Int).SetBytes(word), modulus).FillBytes(word)
+		return fmt.Sprintf("add %s to %s", name, w.Name), true
+	}
+	i -= len(words)
+	publicInputs[i].Add(publicInputs[i], fr.Modulus())
+	return fmt.Sprintf("add r to public input %d", i), true
+}
+
+// negatePoint replaces a point (x, y) of the proof by (x, p-y)
+func negatePoint(rng *rand.Rand, proof []byte, publicInputs []*big.Int) (string, bool) {
+	var candidates []calldata.Field
+	for _, f := range fieldsOf(proof, calldata.Point) {
+		if !isZero(proof[f.Offset : f.Offset+64]) {
+			candidates = append(candidates, f)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	f := candidates[rng.Intn(len(candidates))]
+	y := proof[f.Offset+32 : f.Offset+64]
+	new(big.Int).Sub(fp.Modulus(), new(big.Int).SetBytes(y)).FillBytes(y)
+	return fmt.Sprintf("negate %s", f.Name), true
+}
+
+// swapPoints swaps two distinct points of the proof
+func swapPoints(rng *rand.Rand, proof []byte, publicInputs []*big.Int) (string, bool) {
+	fields := fieldsOf(proof, calldata.Point)
+	var pairs [][2]calldata.Field
+	for i := range fields {
+		for j := i + 1; j < len(fields); j++ {
+			a := proof[fields[i].Offset : fields[i].Offset+64]
+			b := proof[fields[j].Offset : fields[j].Offset+64]
+			if string(a) != string(b) {
+				pairs = append(pairs, [2]calldata.Field{fields[i], fields[j]})
+			}
+		}
+	}
+	if len(pairs) == 0 {
+		return "", false
+	}
+	p := pairs[rng.Intn(len(pairs))]
+	tmp := make([]byte, 64)
+	copy(tmp, proof[p[0].Offset:p[0].Offset+64])
+	copy(proof[p[0].Offset:p[0].Offset+64], proof[p[1].Offset:p[1].Offset+64])
+	copy(proof[p[1].Offset:p[1].Offset+64], tmp)
+	return fmt.Sprintf("swap %s and %s", p[0].Name, p[1].Name), true
+}
+
+// fieldsOf returns the fields of kind of a well sized proof
+func fieldsOf(proof []byte, kind calldata.Kind) []calldata.Field {
+	nbCommitments, err := calldata.NbCommitments(len(proof))
+	if err != nil {
+		panic(err)
+	}
+	var res []calldata.Field
+	for _, f := range calldata.ProofLayout(nbCommitments) {
+		if f.Kind == kind {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// fieldAt returns the field of a well sized proof containing the byte at offset
+func fieldAt(proof []byte, offset int) calldata.Field {
+	nbCommitments, err := calldata.NbCommitments(len(proof))
+	if err != nil {
+		panic(err)
+	}
+	fields := calldata.ProofLayout(nbCommitments)
+	for _, f := range fields {
+		if offset < f.Offset+f.Size() {
+			return f
+		}
+	}
+	panic("offset out of the proof")
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// mutate applies a random mutation to a copy of proof and publicInputs
+func mutate(rng *rand.Rand, proof []byte, publicInputs []*big.Int) (string, []byte, []*big.Int) {
+	for {
+		p := make([]byte, len(proof))
+		copy(p, proof)
+		pi := make([]*big.Int, len(publicInputs))
+		for i := range publicInputs {
+			pi[i] = new(big.Int).Set(publicInputs[i])
+		}
+		if description, ok := mutations[rng.Intn(len(mutations))](rng, p, pi); ok {
+			return description, p, pi
+		}
+	}
+}
+
+// fuzzer holds what the workers share
+type fuzzer struct {
+	example *circuits.Example
+	setup   *circuits.Setup
+	corpus  string
+
+	mu            sync.Mutex
+	runs          int
+	disagreements int
+}
+
+// check runs both verifiers on tc, and records tc in the corpus if they disagree.
+// It returns the number of disagreements, 0 or 1.
+func (f *fuzzer) check(r *evmrun.Runner, tc *testCase) (int, error) {
+
+	agree := compare(f.setup.VK, r, tc)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.runs++
+	if agree {
+		return 0, nil
+	}
+	f.disagreements++
+	fmt.Printf("DISAGREEMENT %s: gnark %s, solidity %s\n", tc.Mutation, tc.Gnark, tc.Solidity)
+	return 1, f.save(tc)
+}
+
+// save writes tc to the corpus, the name of the file is derived from the proof
+// and the public inputs
+func (f *fuzzer) save(tc *testCase) error {
+	data, err := calldata.EncodeVerifyCall("Verify", tc.Proof, tc.publicInputs())
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(tc, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.corpus, 0755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%x.json", crypto.Keccak256(data)[:8])
+	return os.WriteFile(filepath.Join(f.corpus, name), append(content, '\n'), 0644)
+}
+
+// fuzz generates the proof number index, checks that both verifiers accept it,
+// then checks nbMutations mutations of it
+func (f *fuzzer) fuzz(r *evmrun.Runner, seed int64, index, nbMutations int) error {
+
+	// one rng per proof, the mutations don't depend on the scheduling of the workers
+	rng := rand.New(rand.NewSource(seed + int64(index)))
+
+	proof, pi, err := f.setup.Prove(f.example.RandomAssignment(rng))
+	if err != nil {
+		return fmt.Errorf("%s, proof %d: %w", f.example.Name, index, err)
+	}
+	proofBytes := calldata.MarshalProof(*proof)
+	publicInputs := make([]*big.Int, len(pi))
+	for i := range pi {
+		publicInputs[i] = new(big.Int)
+		pi[i].BigInt(publicInputs[i])
+	}
+
+	genuine := newTestCase(f.example.Name, "none", proofBytes, publicInputs)
+	if _, err := f.check(r, genuine); err != nil {
+		return err
+	}
+	if genuine.Gnark != "accepted" || genuine.Solidity != "accepted" {
+		return fmt.Errorf("%s, proof %d: gnark %s, solidity %s", f.example.Name, index, genuine.Gnark, genuine.Solidity)
+	}
+
+	for i := 0; i < nbMutations; i++ {
+		description, p, pi := mutate(rng, proofBytes, publicInputs)
+		if _, err := f.check(r, newTestCase(f.example.Name, description, p, pi)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replay checks the cases of the corpus which are proofs of the fuzzed circuit
+func (f *fuzzer) replay(r *evmrun.Runner) error {
+	files, err := filepath.Glob(filepath.Join(f.corpus, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var tc testCase
+		if err := json.Unmarshal(content, &tc); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if tc.Circuit != f.example.Name {
+			continue
+		}
+		n, err := f.check(r, &tc)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			fmt.Printf("%s: both verifiers agree now (%s)\n", file, tc.Gnark)
+		}
+	}
+	return nil
+}
+
+func main() {
+
+	circuit := flag.String("circuit", "", "example circuit to fuzz, every one by default: "+circuits.Names())
+	nbProofs := flag.Int("proofs", 16, "number of random proofs of each circuit")
+	nbMutations := flag.Int("mutations", 64, "number of mutations of each proof")
+	nbWorkers := flag.Int("workers", runtime.NumCPU(), "number of concurrent workers")
+	seed := flag.Int64("seed", 1, "seed of the random witnesses and mutations")
+	corpus := flag.String("corpus", filepath.Join("cmd", "difffuzz", "testdata"), "directory of the disagreements")
+	solcPath := flag.String("solc", "solc", "path of the solc binary")
+	flag.Parse()
+
+	examples := circuits.Examples
+	if *circuit != "" {
+		example, err := circuits.Get(*circuit)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		examples = []circuits.Example{*example}
+	}
+
+	runs, disagreements := 0, 0
+	for i := range examples {
+		f, err := run(&examples[i], *corpus, solc.Options{Path: *solcPath}, *nbProofs, *nbMutations, *nbWorkers, *seed)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s: %d runs, %d disagreements\n", examples[i].Name, f.runs, f.disagreements)
+		runs += f.runs
+		disagreements += f.disagreements
+	}
+
+	fmt.Printf("%d runs, %d disagreements\n", runs, disagreements)
+	if disagreements != 0 {
+		fmt.Fprintln(os.Stderr, "gnark and Verifier.sol disagree, see "+*corpus)
+		os.Exit(1)
+	}
+}
+
+// deploy generates the verifier of vk as a contract, compiles it and deploys it
+// on an in-memory EVM
+func deploy(vk *bn254plonk.VerifyingKey, solcOpts solc.Options) (*evmrun.Runner, error) {
+
+	// the examples are set up with the test SRS
+	sources, err := tmpl.GenerateVerifierSources(*vk, tmpl.Options{Kind: tmpl.Contract, AllowTestSRS: true})
+	if err != nil {
+		return nil, err
+	}
+	contracts, err := solc.Compile(sources, solcOpts)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := solc.Find(contracts, "PlonkVerifier")
+	if err != nil {
+		return nil, err
+	}
+	return evmrun.Deploy(verifier.Bin)
+}
+
+// run fuzzes example: the cases of the corpus are replayed, then nbProofs proofs
+// are mutated by nbWorkers workers
+func run(example *circuits.Example, corpus string, solcOpts solc.Options, nbProofs, nbMutations, nbWorkers int, seed int64) (*fuzzer, error) {
+
+	setup, err := example.Setup()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", example.Name, err)
+	}
+	f := &fuzzer{example: example, setup: setup, corpus: corpus}
+
+	r, err := deploy(setup.VK, solcOpts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", example.Name, err)
+	}
+	if err := f.replay(r); err != nil {
+		return nil, err
+	}
+
+	jobs := make(chan int)
+	errs := make(chan error, nbWorkers)
+	var wg sync.WaitGroup
+	for w := 0; w < nbWorkers; w++ {
+		wg.Add(1)
+		go func(r *evmrun.Runner) {
+			defer wg.Done()
+			for index := range jobs {
+				if err := f.fuzz(r, seed, index, nbMutations); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(r.Copy())
+	}
+
+	// stop handing out proofs at the first error, the workers which failed
+	// don't read jobs anymore
+	var firstErr error
+loop:
+	for index := 0; index < nbProofs; index++ {
+		select {
+		case jobs <- index:
+		case firstErr = <-errs:
+			break loop
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+	if firstErr == nil {
+		firstErr = <-errs
+	}
+	return f, firstErr
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/solc"
+)
+
+// encodePublicInputs concatenates the public inputs as 32 bytes words
+func encodePublicInputs(publicInputs []*big.Int) []byte {
+	res := make([]byte, 32*len(publicInputs))
+	for i := range publicInputs {
+		publicInputs[i].FillBytes(res[32*i : 32*(i+1)])
+	}
+	return res
+}
+
+// decodePublicInputs is the inverse of encodePublicInputs, an incomplete last
+// word is dropped
+func decodePublicInputs(data []byte) []*big.Int {
+	res := make([]*big.Int, len(data)/32)
+	for i := range res {
+		res[i] = new(big.Int).SetBytes(data[32*i : 32*(i+1)])
+	}
+	return res
+}
+
+// FuzzVerify compares gnark's verifier and Verify of Verifier.sol, as the
+// command does, on inputs mutated by go test's fuzzing engine from a valid proof
+// of com-fiat-shamir and from the corpus.
+func FuzzVerify(f *testing.F) {
+
+	if _, err := exec.LookPath("solc"); err != nil {
+		f.Skip("solc is not in $PATH")
+	}
+
+	example, err := circuits.Get("com-fiat-shamir")
+	if err != nil {
+		f.Fatal(err)
+	}
+	setup, err := example.Setup()
+	if err != nil {
+		f.Fatal(err)
+	}
+	r, err := deploy(setup.VK, solc.Options{})
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	proof, pi, err := setup.Prove(example.Assignment())
+	if err != nil {
+		f.Fatal(err)
+	}
+	publicInputs := make([]*big.Int, len(pi))
+	for i := range pi {
+		publicInputs[i] = pi[i].BigInt(new(big.Int))
+	}
+	f.Add(calldata.MarshalProof(*proof), encodePublicInputs(publicInputs))
+
+	files, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		var tc testCase
+		if err := json.Unmarshal(content, &tc); err != nil {
+			f.Fatalf("%s: %v", file, err)
+		}
+		if tc.Circuit == example.Name {
+			f.Add([]byte(tc.Proof), encodePublicInputs(tc.publicInputs()))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, proof, publicInputs []byte) {
+		tc := newTestCase(example.Name, "fuzz", proof, decodePublicInputs(publicInputs))
+		if !compare(setup.VK, r, tc) {
+			t.Errorf("gnark %s, solidity %s", tc.Gnark, tc.Solidity)
+		}
+	})
+}
@@ -0,0 +1,267 @@
+// Command e2e runs the whole pipeline on every example circuit: the circuit is
+// compiled, proven, its verifier generated and compiled with the local solc, and
+// the proof verified on a simulated backend.
+//
+// The examples have 0 (sb-fiat-shamir), 1 (com-fiat-shamir) and 3
+// (multiple-commitments) BSB22 commitments. Each one is checked with its verifier
+// generated as a contract, whose Verify is called directly, and as a library,
+// called through test_verifier_go and test_verifier() of TestVerifier.sol. Every
+// check is run with an eth_call and with a mined transaction (package evmtest):
+// the valid proof must be accepted, and the proof with a wrong public input, or
+// a wrong opening if the circuit has no public input, rejected.
+//
+// Usage:
+//
+//	go run ./cmd/e2e [-circuit com-fiat-shamir] [-solc solc]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"text/tabwriter"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/evmtest"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// gasLimit of the calls, the default of evmtest is too low for 3 commitments
+const gasLimit = 5000000
+
+// proven is an example with a valid proof
+type proven struct {
+	setup        *circuits.Setup
+	proof        []byte
+	publicInputs []*big.Int
+	pi           fr.Vector
+
+	// wrong is the proof, and wrongPublicInputs the public inputs, of a call which
+	// must be rejected
+	wrong             []byte
+	wrongPublicInputs []*big.Int
+}
+
+func prove(example *circuits.Example) (*proven, error) {
+
+	setup, err := example.Setup()
+	if err != nil {
+		return nil, err
+	}
+	proof, pi, err := setup.Prove(example.Assignment())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &proven{setup: setup, proof: calldata.MarshalProof(*proof), pi: pi}
+	res.publicInputs = make([]*big.Int, len(pi))
+	for i := range pi {
+		res.publicInputs[i] = new(big.Int)
+		pi[i].BigInt(res.publicInputs[i])
+	}
+
+	res.wrong = res.proof
+	res.wrongPublicInputs = res.publicInputs
+	if len(pi) != 0 {
+		// x+1 in place of the first public input x
+		var x fr.Element
+		x.Add(&pi[0], new(fr.Element).SetOne())
+		res.wrongPublicInputs = append([]*big.Int{x.BigInt(new(big.Int))}, res.publicInputs[1:]...)
+	} else {
+		// t(ζ)+1 in place of the claimed value t(ζ)
+		f := fieldNamed(calldata.ProofLayout(len(proof.Bsb22Commitments)), "quotient_polynomial_at_zeta")
+		var t fr.Element
+		t.SetBytes(res.proof[f.Offset : f.Offset+fr.Bytes])
+		t.Add(&t, new(fr.Element).SetOne())
+		b := t.Bytes()
+		res.wrong = append([]byte{}, res.proof...)
+		copy(res.wrong[f.Offset:f.Offset+fr.Bytes], b[:])
+	}
+
+	return res, nil
+}
+
+// fieldNamed returns the field of the proof layout called name
+func fieldNamed(fields []calldata.Field, name string) *calldata.Field {
+	for i := range fields {
+		if fields[i].Name == name {
+			return &fields[i]
+		}
+	}
+	panic("no field " + name + " in the proof layout")
+}
+
+// check is a row of the matrix
+type check struct {
+	circuit  string
+	kind     tmpl.Kind
+	method   string
+	transact bool
+
+	// gas of the valid call, err the first failed expectation
+	gas uint64
+	err error
+}
+
+// compile generates the verifier of p as kind and compiles it. It returns the
+// creation code of the verifier, or of TestVerifier for a library.
+func compile(p *proven, kind tmpl.Kind, solcOpts solc.Options) ([]byte, error) {
+
+	// the examples are set up with the test SRS
+	opts := tmpl.Options{Kind: kind, AllowTestSRS: true}
+	name := "PlonkVerifier"
+	sources, err := tmpl.GenerateVerifierSources(*p.setup.VK, opts)
+	if err != nil {
+		return nil, err
+	}
+	if kind == tmpl.Library {
+		name = "TestVerifier"
+		proof, err := calldata.UnmarshalProof(p.proof)
+		if err != nil {
+			return nil, err
+		}
+		if sources[opts.Files().TestVerifier], err = tmpl.GenerateTestVerifierSource(proof, p.pi, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	contracts, err := solc.Compile(sources, solcOpts)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := solc.Find(contracts, name)
+	if err != nil {
+		return nil, err
+	}
+	return contract.Bin, nil
+}
+
+// run fills c: the valid call must be accepted, the wrong one rejected
+func (c *check) run(ctx context.Context, v *evmtest.Verifier, p *proven) {
+
+	verify := v.Call
+	if c.transact {
+		verify = v.Transact
+	}
+
+	if c.method == "test_verifier" {
+		// the proof and the public inputs are hardcoded in TestVerifier.sol
+		var ok bool
+		ok, c.gas, c.err = callNoArgs(ctx, v, c.transact)
+		if !ok && c.err == nil {
+			c.err = errors.New("the valid proof is rejected")
+		}
+		return
+	}
+
+	ok, gas, err := verify(ctx, p.proof, p.publicInputs)
+	if !ok {
+		c.err = fmt.Errorf("the valid proof is rejected: %w", err)
+		return
+	}
+	c.gas = gas
+	if ok, _, _ := verify(ctx, p.wrong, p.wrongPublicInputs); ok {
+		c.err = errors.New("the wrong proof is accepted")
+	}
+}
+
+// callNoArgs calls test_verifier() on TestVerifier
+func callNoArgs(ctx context.Context, v *evmtest.Verifier, transact bool) (bool, uint64, error) {
+	selector := crypto.Keccak256([]byte("test_verifier()"))[:4]
+	if transact {
+		return v.TransactRaw(ctx, selector)
+	}
+	return v.CallRaw(ctx, selector)
+}
+
+func main() {
+
+	circuit := flag.String("circuit", "", "example circuit to check, every one by default: "+circuits.Names())
+	solcPath := flag.String("solc", "solc", "path of the solc binary")
+	flag.Parse()
+
+	examples := circuits.Examples
+	if *circuit != "" {
+		example, err := circuits.Get(*circuit)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		examples = []circuits.Example{*example}
+	}
+
+	checks, err := run(examples, solc.Options{Path: *solcPath})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	failed := false
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "circuit\tkind\tmethod\tvia\tgas\tresult\t\n")
+	for _, c := range checks {
+		via := "eth_call"
+		if c.transact {
+			via = "transaction"
+		}
+		result := "ok"
+		if c.err != nil {
+			result = "FAILED: " + c.err.Error()
+			failed = true
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\t\n", c.circuit, c.kind, c.method, via, c.gas, result)
+	}
+	tw.Flush()
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// run checks every example, it returns an error if a step before the
+// verification fails
+func run(examples []circuits.Example, solcOpts solc.Options) ([]check, error) {
+
+	ctx := context.Background()
+	backend, err := evmtest.NewBackend(evmtest.WithGasLimit(gasLimit))
+	if err != nil {
+		return nil, err
+	}
+	defer backend.Close()
+
+	var res []check
+	for i := range examples {
+		p, err := prove(&examples[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", examples[i].Name, err)
+		}
+		for _, kind := range []tmpl.Kind{tmpl.Contract, tmpl.Library} {
+			methods := []string{"Verify"}
+			if kind == tmpl.Library {
+				methods = []string{"test_verifier_go", "test_verifier"}
+			}
+			bin, err := compile(p, kind, solcOpts)
+			if err != nil {
+				return nil, fmt.Errorf("%s, %s: %w", examples[i].Name, kind, err)
+			}
+			for _, method := range methods {
+				v, err := backend.Deploy(ctx, bin, evmtest.WithMethod(method))
+				if err != nil {
+					return nil, fmt.Errorf("%s, %s: %w", examples[i].Name, kind, err)
+				}
+				for _, transact := range []bool{false, true} {
+					c := check{circuit: examples[i].Name, kind: kind, method: method, transact: transact}
+					c.run(ctx, v, p)
+					res = append(res, c)
+				}
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/consensys/plonk-solidity/evmtest"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
+)
+
+func TestE2E(t *testing.T) {
+
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip("solc is not in $PATH")
+	}
+
+	tests := []struct {
+		circuit       string
+		nbCommitments int
+	}{
+		{"sb-fiat-shamir", 0},
+		{"com-fiat-shamir", 1},
+		{"multiple-commitments", 3},
+	}
+	calls := []struct {
+		kind    tmpl.Kind
+		methods []string
+	}{
+		{tmpl.Contract, []string{"Verify"}},
+		{tmpl.Library, []string{"test_verifier_go", "test_verifier"}},
+	}
+
+	ctx := context.Background()
+	backend, err := evmtest.NewBackend(evmtest.WithGasLimit(gasLimit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.circuit, func(t *testing.T) {
+
+			example, err := circuits.Get(tt.circuit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, err := prove(example)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := len(p.setup.VK.Qcp); n != tt.nbCommitments {
+				t.Fatalf("%d BSB22 commitments, expected %d", n, tt.nbCommitments)
+			}
+
+			for _, call := range calls {
+				t.Run(call.kind.String(), func(t *testing.T) {
+
+					bin, err := compile(p, call.kind, solc.Options{})
+					if err != nil {
+						t.Fatal(err)
+					}
+					for _, method := range call.methods {
+						v, err := backend.Deploy(ctx, bin, evmtest.WithMethod(method))
+						if err != nil {
+							t.Fatal(err)
+						}
+						for _, transact := range []bool{false, true} {
+							c := check{circuit: tt.circuit, kind: call.kind, method: method, transact: transact}
+							via := "eth_call"
+							if transact {
+								via = "transaction"
+							}
+							t.Run(fmt.Sprintf("%s/%s", method, via), func(t *testing.T) {
+								c.run(ctx, v, p)
+								if c.err != nil {
+									t.Fatal(c.err)
+								}
+							})
+						}
+					}
+				})
+			}
+		})
+	}
+}
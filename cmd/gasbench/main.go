@@ -0,0 +1,409 @@
+// Command gasbench measures the gas used by Verify and the size of its calldata
+// across domain sizes, public input counts and BSB22 commitment counts.
+//
+// Each cell of the benchmark is a parametric circuit (circuits.Parametric): its
+// production verifier is generated as a contract, compiled with the local solc,
+// and called with a valid proof on an in-memory EVM. Without -grid, each
+// dimension is swept while the two others keep their value in -base; with -grid
+// every combination is measured. The results are written as CSV and JSON, and
+// compared with the baseline: the command fails if the gas of a cell exceeds its
+// baseline by more than -tolerance percent. -update overwrites the baseline with
+// the results, after a deliberate change of the template or of solc. The
+// baseline is measured with solc pinnedSolc, the command refuses another version
+// unless -solc-version says so.
+//
+// Each measure is also compared with the prediction of package gasmodel: the
+// command fails if the error of a cell exceeds -model-tolerance percent. -fit
+// refits the coefficients of the model on the measures first, and prints them.
+//
+// Usage:
+//
+//	go run ./cmd/gasbench [-logsizes 4,8,12] [-publics 0,1,100] [-commitments 0,1,3] [-base 10,1,1] [-grid] [-out .] [-update] [-fit] [-solc-version 0.8.19]
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/evmrun"
+	"github.com/consensys/plonk-solidity/gasmodel"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
+)
+
+// pinnedSolc is the version of solc testdata/baseline.json is measured with
+const pinnedSolc = "0.8.19"
+
+// cell is a point of the benchmark
+type cell struct {
+	LogSize        int `json:"log_size"`
+	NbPublicInputs int `json:"public_inputs"`
+	NbCommitments  int `json:"commitments"`
+}
+
+func (c cell) String() string {
+	return fmt.Sprintf("2^%d, %d public inputs, %d commitments", c.LogSize, c.NbPublicInputs, c.NbCommitments)
+}
+
+// result is the measure of a cell
+type result struct {
+	cell
+
+	// DomainSize is the size of the domain of the circuit, it is larger than
+	// 2^LogSize if the public inputs and commitments don't fit in half of it
+	DomainSize uint64 `json:"domain_size"`
+
+	// Gas is the gas used by the execution of Verify, IntrinsicGas the gas of a
+	// transaction carrying its calldata on top of it
+	Gas          uint64 `json:"gas"`
+	IntrinsicGas uint64 `json:"intrinsic_gas"`
+
+	// CalldataBytes is the size of the calldata of Verify, selector included
+	CalldataBytes int `json:"calldata_bytes"`
+
+	// Estimate is the gas predicted by gasmodel, EstimatedCalldataBytes the size
+	// of the calldata
+	Estimate               uint64 `json:"estimate"`
+	EstimatedCalldataBytes int    `json:"estimated_calldata_bytes"`
+
+	vk *bn254plonk.VerifyingKey
+}
+
+var csvHeader = []string{"log_size", "public_inputs", "commitments", "domain_size", "gas", "intrinsic_gas", "calldata_bytes", "estimate", "estimated_calldata_bytes"}
+
+func (r *result) csvRecord() []string {
+	return []string{
+		strconv.Itoa(r.LogSize),
+		strconv.Itoa(r.NbPublicInputs),
+		strconv.Itoa(r.NbCommitments),
+		strconv.FormatUint(r.DomainSize, 10),
+		strconv.FormatUint(r.Gas, 10),
+		strconv.FormatUint(r.IntrinsicGas, 10),
+		strconv.Itoa(r.CalldataBytes),
+		strconv.FormatUint(r.Estimate, 10),
+		strconv.Itoa(r.EstimatedCalldataBytes),
+	}
+}
+
+// parseInts parses a comma separated list of integers
+func parseInts(s string) ([]int, error) {
+	var res []int
+	for _, f := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	return res, nil
+}
+
+// cells returns the cells to measure: the sweeps of each dimension around base,
+// or every combination if grid is set
+func cells(logSizes, publics, commitments []int, base cell, grid bool) []cell {
+	var res []cell
+	seen := make(map[cell]bool)
+	add := func(c cell) {
+		if !seen[c] {
+			seen[c] = true
+			res = append(res, c)
+		}
+	}
+
+	if grid {
+		for _, l := range logSizes {
+			for _, p := range publics {
+				for _, c := range commitments {
+					add(cell{l, p, c})
+				}
+			}
+		}
+		return res
+	}
+
+	for _, l := range logSizes {
+		add(cell{l, base.NbPublicInputs, base.NbCommitments})
+	}
+	for _, p := range publics {
+		add(cell{base.LogSize, p, base.NbCommitments})
+	}
+	for _, c := range commitments {
+		add(cell{base.LogSize, base.NbPublicInputs, c})
+	}
+	return res
+}
+
+// measure runs Verify on a valid proof of the parametric circuit of c
+func measure(c cell, solcOpts solc.Options) (result, error) {
+
+	res := result{cell: c}
+
+	example := circuits.Parametric(c.LogSize, c.NbPublicInputs, c.NbCommitments)
+	setup, err := example.Setup()
+	if err != nil {
+		return res, err
+	}
+	proof, pi, err := setup.Prove(example.Assignment())
+	if err != nil {
+		return res, err
+	}
+	publicInputs := make([]*big.Int, len(pi))
+	for i := range pi {
+		publicInputs[i] = new(big.Int)
+		pi[i].BigInt(publicInputs[i])
+	}
+	res.DomainSize = setup.VK.Size
+	res.vk = setup.VK
+
+	// the parametric circuits are set up with the test SRS
+	sources, err := tmpl.GenerateVerifierSources(*setup.VK, tmpl.Options{Kind: tmpl.Contract, AllowTestSRS: true})
+	if err != nil {
+		return res, err
+	}
+	contracts, err := solc.Compile(sources, solcOpts)
+	if err != nil {
+		return res, err
+	}
+	verifier, err := solc.Find(contracts, "PlonkVerifier")
+	if err != nil {
+		return res, err
+	}
+	runner, err := evmrun.Deploy(verifier.Bin)
+	if err != nil {
+		return res, err
+	}
+
+	data, err := calldata.EncodeVerifyCall("Verify", calldata.MarshalProof(*proof), publicInputs)
+	if err != nil {
+		return res, err
+	}
+	call := runner.Call(data)
+	if !call.Accepted {
+		return res, fmt.Errorf("the valid proof is rejected: %w", call.Err)
+	}
+	res.Gas = call.ExecutionGas
+	res.CalldataBytes = len(data)
+	res.IntrinsicGas, err = evmrun.IntrinsicGas(data)
+	return res, err
+}
+
+func writeCSV(path string, results []result) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	w.Write(csvHeader)
+	for i := range results {
+		w.Write(results[i].csvRecord())
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeJSON(path string, results []result) error {
+	data, err := json.MarshalIndent(results, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func readJSON(path string) ([]result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res []result
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return res, nil
+}
+
+// compare prints the results next to the baseline, and returns the number of
+// cells whose gas exceeds the baseline by more than tolerance percent
+func compare(results, baseline []result, tolerance float64) (int, error) {
+
+	previous := make(map[cell]result, len(baseline))
+	for _, b := range baseline {
+		previous[b.cell] = b
+	}
+
+	regressions := 0
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "cell\tdomain\tgas\tbaseline\tdelta\tcalldata\t\n")
+	for _, r := range results {
+		b, ok := previous[r.cell]
+		if !ok {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t-\tnew\t%d\t\n", r.cell, r.DomainSize, r.Gas, r.CalldataBytes)
+			continue
+		}
+		delta := 100 * (float64(r.Gas) - float64(b.Gas)) / float64(b.Gas)
+		status := ""
+		if delta > tolerance {
+			status = "REGRESSION"
+			regressions++
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%+.2f%%\t%d\t%s\n", r.cell, r.DomainSize, r.Gas, b.Gas, delta, r.CalldataBytes, status)
+	}
+	return regressions, tw.Flush()
+}
+
+// checkModel sets the estimate of gasmodel in each result, with coefficients
+// fitted on the results if fit is set, prints the error of the model, and
+// returns the number of cells whose error exceeds tolerance percent
+func checkModel(results []result, fit bool, tolerance float64) (int, error) {
+
+	coefficients := gasmodel.DefaultCoefficients
+	if fit {
+		measures := make([]gasmodel.Measure, len(results))
+		for i, r := range results {
+			measures[i] = gasmodel.Measure{VK: r.vk, NbPublicInputs: r.NbPublicInputs, Gas: r.Gas}
+		}
+		var err error
+		if coefficients, err = gasmodel.Fit(measures); err != nil {
+			return 0, err
+		}
+		fmt.Printf("fitted coefficients: %+v\n", coefficients)
+	}
+
+	misses := 0
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "cell\tgas\testimate\terror\tcalldata\testimate\t\n")
+	for i := range results {
+		r := &results[i]
+		e := gasmodel.EstimateGasWith(r.vk, r.NbPublicInputs, coefficients)
+		r.Estimate = e.Gas
+		r.EstimatedCalldataBytes = e.Calldata.Bytes
+
+		delta := 100 * (float64(r.Estimate) - float64(r.Gas)) / float64(r.Gas)
+		status := ""
+		if delta > tolerance || delta < -tolerance || r.EstimatedCalldataBytes != r.CalldataBytes {
+			status = "MISS"
+			misses++
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%+.2f%%\t%d\t%d\t%s\n", r.cell, r.Gas, r.Estimate, delta, r.CalldataBytes, r.EstimatedCalldataBytes, status)
+	}
+	return misses, tw.Flush()
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+
+	logSizesFlag := flag.String("logsizes", "4,6,8,10,12,14,16,18,20", "log2 of the domain sizes")
+	publicsFlag := flag.String("publics", "0,1,2,4,8,16,32,64,128,256,512", "numbers of public inputs")
+	commitmentsFlag := flag.String("commitments", "0,1,2,3,4,6,8", "numbers of BSB22 commitments")
+	baseFlag := flag.String("base", "10,1,1", "log size, public inputs and commitments of the cell around which each dimension is swept")
+	grid := flag.Bool("grid", false, "measure every combination instead of the sweeps")
+	out := flag.String("out", ".", "folder of gasbench.csv and gasbench.json")
+	baselinePath := flag.String("baseline", filepath.Join("cmd", "gasbench", "testdata", "baseline.json"), "baseline of the gas")
+	tolerance := flag.Float64("tolerance", 1, "gas increase over the baseline tolerated, in percent")
+	update := flag.Bool("update", false, "overwrite the baseline with the results")
+	solcPath := flag.String("solc", "solc", "path of the solc binary")
+	solcVersion := flag.String("solc-version", pinnedSolc, "version of solc expected, the gas depends on it; empty to accept any")
+	fit := flag.Bool("fit", false, "fit the coefficients of gasmodel on the results")
+	modelTolerance := flag.Float64("model-tolerance", 5, "error of gasmodel tolerated, in percent")
+	flag.Parse()
+
+	logSizes, err := parseInts(*logSizesFlag)
+	if err != nil {
+		return fmt.Errorf("-logsizes: %w", err)
+	}
+	publics, err := parseInts(*publicsFlag)
+	if err != nil {
+		return fmt.Errorf("-publics: %w", err)
+	}
+	commitments, err := parseInts(*commitmentsFlag)
+	if err != nil {
+		return fmt.Errorf("-commitments: %w", err)
+	}
+	base, err := parseInts(*baseFlag)
+	if err != nil || len(base) != 3 {
+		return errors.New("-base expects log size,public inputs,commitments")
+	}
+
+	if *solcVersion != "" {
+		v, err := solc.Version(solc.Options{Path: *solcPath})
+		if err != nil {
+			return err
+		}
+		if v != *solcVersion {
+			return fmt.Errorf("solc %s, expected %s: the baseline is measured with solc %s, set -solc-version to run another one", v, *solcVersion, pinnedSolc)
+		}
+	}
+
+	var results []result
+	for _, c := range cells(logSizes, publics, commitments, cell{base[0], base[1], base[2]}, *grid) {
+		r, err := measure(c, solc.Options{Path: *solcPath})
+		if err != nil {
+			return fmt.Errorf("%s: %w", c, err)
+		}
+		fmt.Fprintf(os.Stderr, "%s: %d gas\n", c, r.Gas)
+		results = append(results, r)
+	}
+
+	misses, err := checkModel(results, *fit, *modelTolerance)
+	if err != nil {
+		return err
+	}
+
+	if err := writeCSV(filepath.Join(*out, "gasbench.csv"), results); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(*out, "gasbench.json"), results); err != nil {
+		return err
+	}
+
+	if *update {
+		if err := writeJSON(*baselinePath, results); err != nil {
+			return err
+		}
+		return modelError(misses)
+	}
+	baseline, err := readJSON(*baselinePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no baseline at %s, run with -update to create it", *baselinePath)
+	}
+	if err != nil {
+		return err
+	}
+	regressions, err := compare(results, baseline, *tolerance)
+	if err != nil {
+		return err
+	}
+	if regressions != 0 {
+		return fmt.Errorf("%d cells use more gas than the baseline", regressions)
+	}
+	return modelError(misses)
+}
+
+// modelError returns an error if gasmodel misses cells
+func modelError(misses int) error {
+	if misses != 0 {
+		return fmt.Errorf("gasmodel misses %d cells, refit it with -fit", misses)
+	}
+	return nil
+}
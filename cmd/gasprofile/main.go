@@ -0,0 +1,110 @@
+// Command gasprofile prints where Verify spends its gas, for the example
+// circuits.
+//
+// For each circuit, the verifier is generated as a contract in profile mode
+// (tmpl.Profile), compiled with the local solc, deployed on an in-memory EVM,
+// and called with a valid proof under profile.Tracer. The table attributes the
+// gas to the sections of Verify (transcript hashing, hash_fr, compute_pi and its
+// batch inversion, the phases of the verification) and to the precompiles they
+// call. The logs of the profiling markers are not counted, so the total is
+// within a few hundred gas of the production verifier.
+//
+// Usage:
+//
+//	go run ./cmd/gasprofile [-circuit com-fiat-shamir] [-solc solc]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/evmrun"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/profile"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
+)
+
+func main() {
+
+	circuit := flag.String("circuit", "", "example circuit to profile, every one by default: "+circuits.Names())
+	solcPath := flag.String("solc", "solc", "path of the solc binary")
+	flag.Parse()
+
+	examples := circuits.Examples
+	if *circuit != "" {
+		example, err := circuits.Get(*circuit)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		examples = []circuits.Example{*example}
+	}
+
+	for i := range examples {
+		if err := run(&examples[i], solc.Options{Path: *solcPath}); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", examples[i].Name, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// run profiles a call to Verify with a valid proof of example
+func run(example *circuits.Example, solcOpts solc.Options) error {
+
+	setup, err := example.Setup()
+	if err != nil {
+		return err
+	}
+	proof, pi, err := setup.Prove(example.Assignment())
+	if err != nil {
+		return err
+	}
+	publicInputs := make([]*big.Int, len(pi))
+	for i := range pi {
+		publicInputs[i] = new(big.Int)
+		pi[i].BigInt(publicInputs[i])
+	}
+
+	opts := tmpl.Options{Kind: tmpl.Contract, Mode: tmpl.Profile}
+	sources, err := tmpl.GenerateVerifierSources(*setup.VK, opts)
+	if err != nil {
+		return err
+	}
+	contracts, err := solc.Compile(sources, solcOpts)
+	if err != nil {
+		return err
+	}
+	verifier, err := solc.Find(contracts, "PlonkVerifier")
+	if err != nil {
+		return err
+	}
+
+	runner, err := evmrun.Deploy(verifier.Bin)
+	if err != nil {
+		return err
+	}
+	tracer := profile.NewTracer()
+	runner.Tracer = tracer
+	res, err := runner.Verify("Verify", calldata.MarshalProof(*proof), publicInputs)
+	if err != nil {
+		return err
+	}
+	if !res.Accepted {
+		return fmt.Errorf("the valid proof is rejected: %w", res.Err)
+	}
+	if tracer.Err() != nil {
+		return tracer.Err()
+	}
+
+	fmt.Printf("%s: domain size %d, %d public inputs, %d commitments, %d gas\n",
+		example.Name, setup.VK.Size, len(publicInputs), len(setup.VK.Qcp), res.ExecutionGas)
+	if err := profile.Print(os.Stdout, tracer.Entries()); err != nil {
+		return err
+	}
+	fmt.Println()
+	return nil
+}
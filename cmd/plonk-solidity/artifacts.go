@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/plonk-solidity/srs"
+)
+
+// readFrom fills dst with the content of the file at path, dst is one of the
+// gnark objects serialised with WriteTo
+func readFrom(path string, dst io.ReaderFrom) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := dst.ReadFrom(f); err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	return nil
+}
+
+func readVerifyingKey(path string) (*bn254plonk.VerifyingKey, error) {
+	var vk bn254plonk.VerifyingKey
+	if err := readFrom(path, &vk); err != nil {
+		return nil, err
+	}
+	return &vk, nil
+}
+
+func readProof(path string) (*bn254plonk.Proof, error) {
+	var proof bn254plonk.Proof
+	if err := readFrom(path, &proof); err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
+// readPublicWitness reads a gnark serialised witness. If the witness is a full
+// witness, only the public part is kept.
+func readPublicWitness(path string) (fr.Vector, error) {
+	w, err := witness.New(ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	if err := readFrom(path, w); err != nil {
+		return nil, err
+	}
+	if w, err = w.Public(); err != nil {
+		return nil, err
+	}
+	pi, ok := w.Vector().(fr.Vector)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected a witness on bn254, got %T", path, w.Vector())
+	}
+	return pi, nil
+}
+
+// readTranscript reads the first powers of τ of a .ptau file, the verifier only
+// depends on [1]G₁, [1]G₂ and [τ]G₂
+func readTranscript(path string) (*srs.Transcript, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	t, err := srs.ReadPtau(f, 0)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return t, nil
+}
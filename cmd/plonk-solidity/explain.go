@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func runExplain(args []string) error {
+
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	vkPath := fs.String("vk", "", "gnark serialised verifying key (required)")
+	hexCalldata := fs.String("calldata", "", "hex encoded calldata of the call to Verify, including the function selector, '-' reads it from stdin")
+	hexProof := fs.String("proof", "", "hex encoded proof, as passed to Verify (alternative to -calldata)")
+	publicInputs := fs.String("public-inputs", "", "comma separated public inputs, decimal or 0x prefixed hex (with -proof)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: plonk-solidity explain -vk vk.bin (-calldata 0x... | -proof 0x... [-public-inputs a,b,c])\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *vkPath == "" {
+		fs.Usage()
+		return errors.New("missing -vk")
+	}
+	vk, err := readVerifyingKey(*vkPath)
+	if err != nil {
+		return err
+	}
+
+	var proof []byte
+	var pi []*big.Int
+	switch {
+	case *hexCalldata != "" && *hexProof != "":
+		return errors.New("-calldata and -proof are mutually exclusive")
+	case *hexCalldata != "":
+		input := *hexCalldata
+		if input == "-" {
+			b, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+			input = strings.TrimSpace(string(b))
+		}
+		proof, pi, err = calldata.DecodeVerifyCall(common.FromHex(input))
+		if err != nil {
+			return err
+		}
+	case *hexProof != "":
+		proof = common.FromHex(*hexProof)
+		if pi, err = parsePublicInputs(*publicInputs); err != nil {
+			return err
+		}
+	default:
+		fs.Usage()
+		return errors.New("one of -calldata or -proof is required")
+	}
+
+	return calldata.Explain(os.Stdout, proof, pi, vk)
+}
+
+func parsePublicInputs(s string) ([]*big.Int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var res []*big.Int
+	for _, e := range strings.Split(s, ",") {
+		v, ok := new(big.Int).SetString(strings.TrimSpace(e), 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid public input %q", e)
+		}
+		res = append(res, v)
+	}
+	return res, nil
+}
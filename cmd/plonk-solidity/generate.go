@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/consensys/plonk-solidity/tmpl"
+)
+
+func runGenerate(args []string) error {
+
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	vkPath := fs.String("vk", "", "gnark serialised verifying key (required)")
+	proofPath := fs.String("proof", "", "gnark serialised proof, hardcoded in TestVerifier.sol")
+	witnessPath := fs.String("witness", "", "gnark serialised public witness of the proof")
+	out := fs.String("out", "contracts", "output folder")
+	var opts tmpl.Options
+	fs.StringVar(&opts.Name, "name", "PlonkVerifier", "name of the verifier library or contract")
+	fs.StringVar(&opts.Pragma, "pragma", "^0.8.4", "solidity version constraint")
+	fs.StringVar(&opts.License, "license", "Apache-2.0", "SPDX license identifier")
+	contract := fs.Bool("contract", false, "generate a deployable contract with an external Verify instead of a library")
+	debugMode := fs.Bool("debug", false, "log the state of Verify after each phase, Verify is then not a view function")
+	profileMode := fs.Bool("profile", false, "mark the sections of Verify with logs to profile its gas, Verify is then not a view function")
+	ptau := fs.String("ptau", "", "powers of tau transcript (.ptau) the vk was set up with, required for a production verifier")
+	fs.BoolVar(&opts.AllowTestSRS, "allow-test-srs", false, "generate a production verifier without -ptau, e.g. from a vk of gnark's test SRS, whose secret is known")
+	var bindings tmpl.Bindings
+	fs.StringVar(&bindings.GoFile, "bindings", "", "compile the sources with solc and write the Go bindings of TestVerifier, or of the verifier contract, in this file")
+	fs.StringVar(&bindings.ABIFolder, "abi", "", "with -bindings, folder of the .abi and .bin files of the bound contract")
+	fs.StringVar(&bindings.Package, "pkg", "contract", "with -bindings, Go package of the bindings")
+	fs.StringVar(&bindings.Type, "type", "Contract", "with -bindings, Go type of the bindings")
+	fs.StringVar(&bindings.Solc.Path, "solc", "solc", "with -bindings, path of the solc binary")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: plonk-solidity generate -vk vk.bin -ptau pot.ptau [-proof proof.bin -witness public.wtns] [-out ./contracts]\n\n")
+		fmt.Fprintf(fs.Output(), "TestVerifier.sol is generated only if -proof and -witness are set.\n")
+		fmt.Fprintf(fs.Output(), "-bindings binds TestVerifier if it is generated, else the verifier, which must then be a -contract.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *vkPath == "" {
+		fs.Usage()
+		return errors.New("missing -vk")
+	}
+	if *contract {
+		opts.Kind = tmpl.Contract
+	}
+	if *debugMode && *profileMode {
+		return errors.New("-debug and -profile are exclusive")
+	}
+	if *debugMode {
+		opts.Mode = tmpl.Debug
+	}
+	if *profileMode {
+		opts.Mode = tmpl.Profile
+	}
+	if (*proofPath == "") != (*witnessPath == "") {
+		return errors.New("-proof and -witness go together")
+	}
+
+	vk, err := readVerifyingKey(*vkPath)
+	if err != nil {
+		return err
+	}
+	if *ptau != "" {
+		if opts.SRS, err = readTranscript(*ptau); err != nil {
+			return err
+		}
+	}
+	sources, err := tmpl.GenerateVerifierSources(*vk, opts)
+	if err != nil {
+		return err
+	}
+
+	if *proofPath != "" {
+		proof, err := readProof(*proofPath)
+		if err != nil {
+			return err
+		}
+		pi, err := readPublicWitness(*witnessPath)
+		if err != nil {
+			return err
+		}
+		if sources[opts.Files().TestVerifier], err = tmpl.GenerateTestVerifierSource(*proof, pi, opts); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		return err
+	}
+	if err := sources.Write(*out); err != nil {
+		return err
+	}
+
+	if bindings.GoFile == "" {
+		return nil
+	}
+	bindings.Contract = opts.Contracts().TestVerifier
+	if _, ok := sources[opts.Files().TestVerifier]; !ok {
+		if !*contract {
+			return errors.New("-bindings without -proof requires -contract, a library has no bytecode")
+		}
+		bindings.Contract = opts.Name
+	}
+	_, err = sources.Bind(bindings)
+	return err
+}
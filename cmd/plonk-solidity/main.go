@@ -0,0 +1,57 @@
+// Command plonk-solidity is a toolbox around the Solidity PLONK verifier.
+//
+// Usage:
+//
+//	plonk-solidity <command> [arguments]
+//
+// The commands are:
+//
+//	generate   generate the Solidity verifier from gnark serialised artifacts
+//	explain    annotate every word of the calldata of a call to Verify
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name  string
+	short string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"generate", "generate the Solidity verifier from gnark serialised artifacts", runGenerate},
+	{"explain", "annotate every word of the calldata of a call to Verify", runExplain},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: plonk-solidity <command> [arguments]\n\ncommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.short)
+	}
+	fmt.Fprintf(os.Stderr, "\nrun 'plonk-solidity <command> -h' for the arguments of a command\n")
+}
+
+func main() {
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			if err := c.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", c.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
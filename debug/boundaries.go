@@ -0,0 +1,195 @@
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Boundaries locates the phases of Verify in the runtime bytecode of a contract,
+// with the source map of solc, so that a Tracer captures the state of any
+// verifier, not only of a verifier generated in debug mode.
+//
+// A phase ends where the function of the next phase starts: the first
+// instruction which solc maps to the definition of compute_alpha_square_lagrange_0
+// ends the challenges, and so on. batch_verify_multi_points, the last phase, ends
+// with the call to the pairing precompile, after which the state is not written.
+type Boundaries struct {
+
+	// hash of the runtime bytecode, the tracer skips the code of other contracts
+	codeHash common.Hash
+
+	// starts[pc] is the phase whose function the instruction at pc belongs to
+	starts map[uint64]Phase
+
+	// stateSize is state_last_mem, the size of the state of the verifier
+	stateSize uint64
+}
+
+var stateLastMem = regexp.MustCompile(`uint256 constant state_last_mem = (0x[0-9a-f]+);`)
+
+// NewBoundaries returns the boundaries of the phases of Verify in contract, the
+// contract whose code runs Verify: the verifier generated as a contract, or a
+// contract using the verifier generated as a library, e.g. TestVerifier. file is
+// the name of the Solidity file of the verifier in contract.SourceList, and
+// source its content, as compiled.
+func NewBoundaries(contract *solc.Contract, file string, source []byte) (*Boundaries, error) {
+
+	fileIndex := -1
+	for i, f := range contract.SourceList {
+		if f == file {
+			fileIndex = i
+		}
+	}
+	if fileIndex < 0 {
+		return nil, fmt.Errorf("%s is not in the source list of %s", file, contract.Name)
+	}
+
+	m := stateLastMem.FindSubmatch(source)
+	if m == nil {
+		return nil, fmt.Errorf("no state_last_mem in %s", file)
+	}
+	stateSize, err := strconv.ParseUint(string(m[1]), 0, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	// the challenges are computed by Verify itself, they have no function
+	ranges := make([]sourceRange, len(phaseNames))
+	for p := PhaseAlphaSquareLagrange; int(p) < len(phaseNames); p++ {
+		if ranges[p], err = functionRange(source, phaseNames[p]); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	entries, err := parseSourceMap(contract.SrcMapRuntime)
+	if err != nil {
+		return nil, fmt.Errorf("source map of %s: %w", contract.Name, err)
+	}
+
+	res := &Boundaries{
+		codeHash:  crypto.Keccak256Hash(contract.BinRuntime),
+		starts:    make(map[uint64]Phase),
+		stateSize: stateSize,
+	}
+	code := contract.BinRuntime
+	pc := uint64(0)
+	for _, e := range entries {
+		if pc >= uint64(len(code)) {
+			break
+		}
+		if e.file == fileIndex {
+			for p := PhaseAlphaSquareLagrange; int(p) < len(phaseNames); p++ {
+				if ranges[p].contains(e.sourceRange) {
+					res.starts[pc] = p
+				}
+			}
+		}
+		pc += instructionSize(vm.OpCode(code[pc]))
+	}
+
+	found := make([]bool, len(phaseNames))
+	for _, p := range res.starts {
+		found[p] = true
+	}
+	for p := PhaseAlphaSquareLagrange; int(p) < len(phaseNames); p++ {
+		if !found[p] {
+			return nil, fmt.Errorf("no instruction of %s maps to %s", contract.Name, p)
+		}
+	}
+
+	return res, nil
+}
+
+// sourceRange is a range of bytes of a source file
+type sourceRange struct {
+	start, length int
+}
+
+func (r sourceRange) contains(s sourceRange) bool {
+	return s.start >= r.start && s.start+s.length <= r.start+r.length
+}
+
+// sourceMapEntry is the source range of an instruction, in the file of index file
+// in the source list, -1 for the code generated by solc
+type sourceMapEntry struct {
+	sourceRange
+	file int
+}
+
+// parseSourceMap decodes a source map, s:l:f:j:m entries separated by ';', in which
+// an empty field repeats the field of the previous entry. Only s, l and f are
+// read.
+func parseSourceMap(m string) ([]sourceMapEntry, error) {
+	if m == "" {
+		return nil, errors.New("empty source map")
+	}
+
+	var res []sourceMapEntry
+	var cur sourceMapEntry
+	for _, item := range strings.Split(m, ";") {
+		fields := strings.Split(item, ":")
+		for i, dst := range []*int{&cur.start, &cur.length, &cur.file} {
+			if i >= len(fields) || fields[i] == "" {
+				continue
+			}
+			v, err := strconv.Atoi(fields[i])
+			if err != nil {
+				return nil, fmt.Errorf("entry %q: %w", item, err)
+			}
+			*dst = v
+		}
+		res = append(res, cur)
+	}
+	return res, nil
+}
+
+// functionRange returns the range of the definition of the function name in
+// source, from the keyword function to its closing brace. The function must be
+// defined exactly once.
+func functionRange(source []byte, name string) (sourceRange, error) {
+
+	definition := regexp.MustCompile(`function\s+` + regexp.QuoteMeta(name) + `\s*\(`)
+	matches := definition.FindAllIndex(source, -1)
+	if len(matches) != 1 {
+		return sourceRange{}, fmt.Errorf("%d definitions of function %s, expected 1", len(matches), name)
+	}
+	start := matches[0][0]
+
+	// the braces in the // comments are skipped
+	depth := 0
+	for i := matches[0][1]; i < len(source); i++ {
+		switch source[i] {
+		case '/':
+			if i+1 < len(source) && source[i+1] == '/' {
+				for i < len(source) && source[i] != '\n' {
+					i++
+				}
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return sourceRange{start, i + 1 - start}, nil
+			}
+		}
+	}
+	return sourceRange{}, fmt.Errorf("function %s is not closed", name)
+}
+
+// instructionSize returns the size in bytes of the instruction op and of its
+// immediate value
+func instructionSize(op vm.OpCode) uint64 {
+	if op >= vm.PUSH1 && op <= vm.PUSH32 {
+		return uint64(op-vm.PUSH1) + 2
+	}
+	return 1
+}
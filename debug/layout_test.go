@@ -0,0 +1,214 @@
+package debug_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/debug"
+	"github.com/consensys/plonk-solidity/evmtest"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/shadow"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	stateConstantRegexp = regexp.MustCompile(`uint256 constant (state_\w+) = (0x[0-9a-f]+);`)
+	stateUseRegexp      = regexp.MustCompile(`\bstate_\w+`)
+	eventRegexp         = regexp.MustCompile(`event PlonkDebugState\(uint256 indexed phase, uint256\[(\d+)\] state\);`)
+	eventIDRegexp       = regexp.MustCompile(`uint256 constant debug_state_event = (0x[0-9a-f]+);`)
+	phaseRegexp         = regexp.MustCompile(`uint256 constant debug_phase_(\w+) = (\d+);`)
+)
+
+// TestStateLayout renders the verifier in each mode, and checks that its state_*
+// constants, and in debug mode its PlonkDebugState event and debug_phase_*
+// constants, are the ones package debug decodes.
+func TestStateLayout(t *testing.T) {
+
+	example := &circuits.Examples[0]
+	setup, err := example.Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mode := range []tmpl.Mode{tmpl.Production, tmpl.Debug, tmpl.Profile} {
+		opts := tmpl.Options{Mode: mode, AllowTestSRS: true}
+		sources, err := tmpl.GenerateVerifierSources(*setup.VK, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verifier := sources[opts.Files().Verifier]
+		isDebug := mode == tmpl.Debug
+
+		// the state_* constants are the offsets of StateLayout, the debug slots
+		// only in debug mode
+		constants := make(map[string]int)
+		for _, m := range stateConstantRegexp.FindAllSubmatch(verifier, -1) {
+			v, err := strconv.ParseInt(string(m[2]), 0, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			constants[string(m[1])] = int(v)
+		}
+		expected := map[string]int{"state_last_mem": debug.StateSize(isDebug)}
+		for _, s := range debug.StateLayout() {
+			if isDebug || !s.DebugOnly {
+				expected["state_"+s.Name] = s.Offset
+			}
+		}
+		for name, offset := range expected {
+			if v, ok := constants[name]; !ok || v != offset {
+				t.Errorf("%s: %s = %#x, expected %#x", mode, name, v, offset)
+			}
+		}
+		for name := range constants {
+			if _, ok := expected[name]; !ok {
+				t.Errorf("%s: %s is not in the state layout", mode, name)
+			}
+		}
+		// Verify reads and writes the state through the constants only
+		for _, m := range stateUseRegexp.FindAll(verifier, -1) {
+			if _, ok := constants[string(m)]; !ok {
+				t.Errorf("%s: %s is used but not declared", mode, m)
+			}
+		}
+
+		event := eventRegexp.FindSubmatch(verifier)
+		if !isDebug {
+			if event != nil {
+				t.Errorf("%s: unexpected PlonkDebugState event", mode)
+			}
+			continue
+		}
+
+		// the event logs the whole state, under the ID the decoder expects
+		if event == nil {
+			t.Fatal("no PlonkDebugState event")
+		}
+		if n, _ := strconv.Atoi(string(event[1])); n*0x20 != debug.StateSize(true) {
+			t.Errorf("the event logs %d words, the state has %d", n, debug.StateSize(true)/0x20)
+		}
+		signature := fmt.Sprintf("PlonkDebugState(uint256,uint256[%s])", event[1])
+		if signature != debug.EventSignature {
+			t.Errorf("event %s, the decoder expects %s", signature, debug.EventSignature)
+		}
+		if id := crypto.Keccak256Hash([]byte(signature)); id != debug.EventID {
+			t.Errorf("the event ID is %s, the decoder expects %s", id, debug.EventID)
+		}
+		if m := eventIDRegexp.FindSubmatch(verifier); m == nil || common.HexToHash(string(m[1])) != debug.EventID {
+			t.Errorf("debug_state_event is not %s", debug.EventID)
+		}
+
+		// the phases are logged with the index of debug.Phases
+		phases := phaseRegexp.FindAllSubmatch(verifier, -1)
+		if len(phases) != len(debug.Phases()) {
+			t.Fatalf("%d phases in the verifier, %d in the decoder", len(phases), len(debug.Phases()))
+		}
+		for i, m := range phases {
+			if string(m[1]) != debug.Phases()[i].String() || string(m[2]) != strconv.Itoa(i) {
+				t.Errorf("debug_phase_%s = %s, expected debug_phase_%s = %d", m[1], m[2], debug.Phases()[i], i)
+			}
+		}
+	}
+}
+
+// TestDecodeLogsAgreesWithShadow sends the valid proof of each example to a
+// verifier generated in debug mode, and checks the states decoded from its logs
+// against the ones computed by package shadow.
+func TestDecodeLogsAgreesWithShadow(t *testing.T) {
+
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip("solc is not in $PATH")
+	}
+
+	for i := range circuits.Examples {
+		example := &circuits.Examples[i]
+		t.Run(example.Name, func(t *testing.T) {
+
+			ctx := context.Background()
+			setup, err := example.Setup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, pi, err := setup.Prove(example.Assignment())
+			if err != nil {
+				t.Fatal(err)
+			}
+			proofBytes := calldata.MarshalProof(*proof)
+			publicInputs := make([]*big.Int, len(pi))
+			for i := range pi {
+				publicInputs[i] = pi[i].BigInt(new(big.Int))
+			}
+
+			opts := tmpl.Options{Kind: tmpl.Contract, Mode: tmpl.Debug}
+			sources, err := tmpl.GenerateVerifierSources(*setup.VK, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			contracts, err := solc.Compile(sources, solc.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifier, err := solc.Find(contracts, opts.Contracts().Verifier)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the logs are kept by a transaction, Verify logs in debug mode so it
+			// is not a view function
+			backend, err := evmtest.NewBackend(evmtest.WithGasLimit(5000000))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer backend.Close()
+			v, err := backend.Deploy(ctx, verifier.Bin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok, _, err := v.Transact(ctx, proofBytes, publicInputs); !ok {
+				t.Fatalf("the valid proof is rejected: %v", err)
+			}
+			logs, err := backend.Client.FilterLogs(ctx, ethereum.FilterQuery{Addresses: []common.Address{v.Address}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ptrs := make([]*types.Log, len(logs))
+			for i := range logs {
+				ptrs[i] = &logs[i]
+			}
+			states, err := debug.DecodeLogs(ptrs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected, err := shadow.Verify(setup.VK, proofBytes, publicInputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(states) != len(debug.Phases()) {
+				t.Fatalf("%d states logged, expected %d", len(states), len(debug.Phases()))
+			}
+			if d := shadow.Diff(expected, states); d != nil {
+				t.Error(d)
+			}
+			// in debug mode, every slot is logged
+			for _, s := range states {
+				for j, v := range s.Values {
+					if v == nil {
+						t.Errorf("%s: state_%s is not decoded", s.Phase, debug.StateLayout()[j].Name)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrNotDebugLog is returned when decoding a log which is not a PlonkDebugState event.
+var ErrNotDebugLog = errors.New("not a PlonkDebugState log")
+
+// State is the state of Verify logged at the end of a phase.
+type State struct {
+	Phase Phase
+
+	// Values of the slots, indexed as StateLayout. A value is nil if it is
+	// unknown, e.g. a slot which Verify never writes, in a state computed by
+	// package shadow.
+	Values []*big.Int
+}
+
+// Get returns the value of the slot state_<name>.
+func (s *State) Get(name string) (*big.Int, bool) {
+	for i, slot := range StateLayout() {
+		if slot.Name == name {
+			return s.Values[i], true
+		}
+	}
+	return nil, false
+}
+
+// DecodeLog decodes a PlonkDebugState log. It returns ErrNotDebugLog if l is
+// another event.
+func DecodeLog(l *types.Log) (State, error) {
+	if len(l.Topics) != 2 || l.Topics[0] != EventID {
+		return State{}, ErrNotDebugLog
+	}
+
+	return newState(l.Topics[1].Big(), l.Data)
+}
+
+// newState decodes the state at the end of phase, of a verifier generated in
+// debug mode or not: the values of the DebugOnly slots are nil in the latter.
+func newState(phase *big.Int, data []byte) (State, error) {
+	if !phase.IsUint64() || phase.Uint64() >= uint64(len(phaseNames)) {
+		return State{}, fmt.Errorf("unknown phase %s", phase)
+	}
+
+	debug := len(data) == StateSize(true)
+	if !debug && len(data) != StateSize(false) {
+		return State{}, fmt.Errorf("the state is %d bytes long, expected %d or %d", len(data), StateSize(false), StateSize(true))
+	}
+
+	layout := StateLayout()
+	res := State{Phase: Phase(phase.Uint64()), Values: make([]*big.Int, len(layout))}
+	for i, slot := range layout {
+		if slot.DebugOnly && !debug {
+			continue
+		}
+		res.Values[i] = new(big.Int).SetBytes(data[slot.Offset : slot.Offset+0x20])
+	}
+	return res, nil
+}
+
+// DecodeLogs decodes the PlonkDebugState logs among logs, in order. The other
+// events are skipped.
+func DecodeLogs(logs []*types.Log) ([]State, error) {
+	var res []State
+	for _, l := range logs {
+		s, err := DecodeLog(l)
+		if errors.Is(err, ErrNotDebugLog) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, nil
+}
+
+// Print writes the slots set by each phase of states to w: the first state is
+// written in full, then only the slots which changed since the previous phase.
+func Print(w io.Writer, states []State) error {
+	layout := StateLayout()
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for i, s := range states {
+		fmt.Fprintf(tw, "%s\n", s.Phase)
+		for j, slot := range layout {
+			if i > 0 && equal(s.Values[j], states[i-1].Values[j]) {
+				continue
+			}
+			if s.Values[j] == nil {
+				fmt.Fprintf(tw, "  %#x\tstate_%s\t-\n", slot.Offset, slot.Name)
+				continue
+			}
+			fmt.Fprintf(tw, "  %#x\tstate_%s\t0x%064x\n", slot.Offset, slot.Name, s.Values[j])
+		}
+	}
+	return tw.Flush()
+}
+
+// equal returns true if a and b are both unknown or both equal to the same value
+func equal(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
@@ -0,0 +1,144 @@
+// Package debug decodes the state of Verify at the end of each phase of the
+// verification: the memory addressed by the state_* constants of Verifier.sol.
+//
+// In debug mode, Verify logs its whole state in a PlonkDebugState event. In
+// every mode, Tracer reads it from the memory of the EVM, at the phase
+// boundaries found with the source map of the bytecode. The state layout below is
+// the single source of truth for the state_* constants and for the decoder.
+package debug
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Slot is a 32 bytes word of the state of Verify.
+type Slot struct {
+
+	// Name of the slot, the Solidity constant is state_<Name>
+	Name string
+
+	// Offset of the slot from the start of the state, in bytes
+	Offset int
+
+	// Comment is emitted above the corresponding constant in Verifier.sol
+	Comment string
+
+	// DebugOnly is true if the slot only exists in debug mode
+	DebugOnly bool
+}
+
+// StateLayout returns the slots of the state of Verify, in memory order. The
+// debug only slots are last.
+func StateLayout() []Slot {
+	var l stateLayout
+
+	l.slot("alpha").Comment = "challenges to check the claimed quotient"
+	l.slot("beta")
+	l.slot("gamma")
+	l.slot("zeta")
+
+	l.slot("sv").Comment = "challenges related to KZG"
+	l.slot("su")
+
+	l.slot("alpha_square_lagrange").Comment = "reusable value"
+
+	l.slot("folded_h_x").Comment = "commitment to H"
+	l.slot("folded_h_y")
+
+	l.slot("linearised_polynomial_x").Comment = "commitment to the linearised polynomial"
+	l.slot("linearised_polynomial_y")
+
+	l.slot("folded_claimed_values").Comment = "Folded proof for the opening of H, linearised poly, l, r, o, s_1, s_2, qcp"
+
+	l.slot("folded_digests_x").Comment = "folded digests of H, linearised poly, l, r, o, s_1, s_2, qcp"
+	l.slot("folded_digests_y")
+
+	l.slot("pi").Comment = "contribution of the public inputs, PI(ζ)"
+
+	l.slot("zeta_power_n_minus_one").Comment = "ζⁿ-1"
+	l.slot("alpha_square_lagrange_one").Comment = "α²L₁(ζ)"
+
+	l.slot("gamma_kzg").Comment = "challenge to fold the openings at ζ"
+
+	check := l.slot("check_var")
+	check.Comment = "/!\\ this slot is used for debugging only"
+	check.DebugOnly = true
+
+	return l.slots
+}
+
+// StateSize returns the size in bytes of the state of Verify, that is the value of
+// the state_last_mem constant.
+func StateSize(debug bool) int {
+	size := 0
+	for _, s := range StateLayout() {
+		if debug || !s.DebugOnly {
+			size += 0x20
+		}
+	}
+	return size
+}
+
+type stateLayout struct {
+	slots []Slot
+	size  int
+}
+
+func (l *stateLayout) slot(name string) *Slot {
+	l.slots = append(l.slots, Slot{Name: name, Offset: l.size})
+	l.size += 0x20
+	return &l.slots[len(l.slots)-1]
+}
+
+// Phase identifies the step of Verify after which the state is logged.
+type Phase uint8
+
+const (
+	// PhaseChallenges is logged once the challenges and PI(ζ) are stored in the state
+	PhaseChallenges Phase = iota
+	PhaseAlphaSquareLagrange
+	PhaseQuotientEval
+	PhaseFoldH
+	PhaseLinearisedPolynomial
+	PhaseGammaKzg
+	PhaseFoldState
+	PhaseBatchVerify
+)
+
+// phaseNames are the names of the phases, they match the functions of Verify in
+// Verifier.sol and give the debug_phase_* constants.
+var phaseNames = []string{
+	PhaseChallenges:           "challenges",
+	PhaseAlphaSquareLagrange:  "compute_alpha_square_lagrange_0",
+	PhaseQuotientEval:         "verify_quotient_poly_eval_at_zeta",
+	PhaseFoldH:                "fold_h",
+	PhaseLinearisedPolynomial: "compute_commitment_linearised_polynomial",
+	PhaseGammaKzg:             "compute_gamma_kzg",
+	PhaseFoldState:            "fold_state",
+	PhaseBatchVerify:          "batch_verify_multi_points",
+}
+
+// Phases returns every phase, in the order in which they are logged.
+func Phases() []Phase {
+	res := make([]Phase, len(phaseNames))
+	for i := range res {
+		res[i] = Phase(i)
+	}
+	return res
+}
+
+func (p Phase) String() string {
+	if int(p) < len(phaseNames) {
+		return phaseNames[p]
+	}
+	return fmt.Sprintf("Phase(%d)", uint8(p))
+}
+
+// EventSignature is the signature of the event logging the state of Verify.
+var EventSignature = fmt.Sprintf("PlonkDebugState(uint256,uint256[%d])", StateSize(true)/0x20)
+
+// EventID is the first topic of the PlonkDebugState logs.
+var EventID common.Hash = crypto.Keccak256Hash([]byte(EventSignature))
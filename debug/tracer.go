@@ -0,0 +1,234 @@
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/consensys/plonk-solidity/reverts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// ErrNoState is returned by TraceCall when no state of Verify was captured.
+var ErrNoState = errors.New("no state of Verify was captured: the call failed before the end of the first phase, or the verifier is not generated in debug mode and the tracer has no Boundaries")
+
+// Tracer is a vm.EVMLogger capturing the state of Verify at the end of each phase:
+// the memory from mload(0x40) to state_last_mem. The state is read from the memory
+// while Verify runs, so it is captured even if the call reverts.
+//
+// With Boundaries, the tracer finds the end of each phase in the bytecode with the
+// source map of solc, it works with the verifiers generated in every mode. Without,
+// it keys on the PlonkDebugState logs, which only a verifier generated in debug
+// mode (tmpl.Debug) emits: under a production or profile verifier, it captures
+// nothing.
+type Tracer struct {
+
+	// States are the states at the end of each phase, in order
+	States []State
+
+	// Reverted is the state when the execution reverted, its Phase is the phase
+	// during which it reverted. It is nil if the execution didn't revert after the
+	// end of the first phase.
+	Reverted *State
+
+	// CallErr is the error of the call traced by TraceCall, decoded with package
+	// reverts if it reverted, nil if it succeeded
+	CallErr error
+
+	boundaries *Boundaries
+
+	// address of the state in memory, and depth of the call running Verify,
+	// known once the first state is captured
+	base  uint64
+	depth int
+
+	err error
+}
+
+// NewTracer returns a tracer to pass to vm.Config, the states are in the tracer
+// once the EVM has run. If b is nil, the tracer reads the states logged by a
+// verifier generated in debug mode.
+func NewTracer(b *Boundaries) *Tracer {
+	return &Tracer{boundaries: b}
+}
+
+// Err returns the first error met while decoding a state.
+func (t *Tracer) Err() error {
+	return t.err
+}
+
+func (t *Tracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.err != nil || err != nil {
+		return
+	}
+	if t.boundaries != nil {
+		t.captureBoundary(pc, op, scope, depth)
+		return
+	}
+
+	switch op {
+	case vm.LOG2:
+		// log2(offset, size, topic0, topic1)
+		stack := scope.Stack
+		if common.Hash(stack.Back(2).Bytes32()) != EventID {
+			return
+		}
+		offset, size := stack.Back(0).Uint64(), stack.Back(1).Uint64()
+		state, err := newState(stack.Back(3).ToBig(), memoryAt(scope.Memory, offset, size))
+		if err != nil {
+			t.err = err
+			return
+		}
+		t.States = append(t.States, state)
+		t.base, t.depth = offset, depth
+
+	case vm.REVERT:
+		t.captureRevert(scope, depth, uint64(StateSize(true)))
+	}
+}
+
+// captureBoundary captures the state if the instruction at pc ends the phase
+// being run
+func (t *Tracer) captureBoundary(pc uint64, op vm.OpCode, scope *vm.ScopeContext, depth int) {
+	if scope.Contract.CodeHash != t.boundaries.codeHash {
+		return
+	}
+
+	phase := Phase(len(t.States))
+	switch {
+	case int(phase)+1 < len(phaseNames):
+		// the phase ends where the function of the next one starts
+		if next, ok := t.boundaries.starts[pc]; !ok || next != phase+1 {
+			break
+		}
+		// the state starts at the free memory pointer, which Verify doesn't move
+		base := new(big.Int).SetBytes(memoryAt(scope.Memory, 0x40, 0x20))
+		if !base.IsUint64() {
+			t.err = fmt.Errorf("free memory pointer %#x out of range", base)
+			return
+		}
+		t.base, t.depth = base.Uint64(), depth
+		t.capture(phase, scope)
+
+	case int(phase)+1 == len(phaseNames) && op == vm.STATICCALL:
+		// batch_verify_multi_points ends with staticcall(gas, 8, ...), the pairing
+		if address := scope.Stack.Back(1); address.IsUint64() && address.Uint64() == 8 {
+			t.capture(phase, scope)
+		}
+	}
+
+	if op == vm.REVERT {
+		t.captureRevert(scope, depth, t.boundaries.stateSize)
+	}
+}
+
+// capture appends the state at t.base, at the end of phase
+func (t *Tracer) capture(phase Phase, scope *vm.ScopeContext) {
+	state, err := newState(big.NewInt(int64(phase)), memoryAt(scope.Memory, t.base, t.boundaries.stateSize))
+	if err != nil {
+		t.err = err
+		return
+	}
+	t.States = append(t.States, state)
+}
+
+// captureRevert sets t.Reverted if Verify reverts after the end of its first
+// phase, size is the size of the state
+func (t *Tracer) captureRevert(scope *vm.ScopeContext, depth int, size uint64) {
+	if len(t.States) == 0 || len(t.States) == len(phaseNames) || t.Reverted != nil || depth != t.depth {
+		return
+	}
+	data := memoryAt(scope.Memory, t.base, size)
+	state, err := newState(big.NewInt(int64(len(t.States))), data)
+	if err != nil {
+		t.err = err
+		return
+	}
+	t.Reverted = &state
+}
+
+func (t *Tracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *Tracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *Tracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+}
+
+func (t *Tracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func (t *Tracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+func (t *Tracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (t *Tracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// memoryAt returns a copy of size bytes of m at offset, padded with zeros past the
+// end of the memory
+func memoryAt(m *vm.Memory, offset, size uint64) []byte {
+	res := make([]byte, size)
+	if data := m.Data(); offset < uint64(len(data)) {
+		copy(res, data[offset:])
+	}
+	return res
+}
+
+// TraceCall runs call on top of the current block of chain, e.g. the Blockchain()
+// of a simulated backend, under NewTracer(b), and returns the tracer holding the
+// states of Verify, and the error of the call in Tracer.CallErr. Nothing is
+// committed.
+//
+// The error is not nil if the call could not be traced: it could not be run, a
+// state could not be decoded, or no state was captured (ErrNoState), e.g.
+// because b is nil and the verifier called is not generated in debug mode. The
+// tracer is nil then.
+func TraceCall(chain *core.BlockChain, call ethereum.CallMsg, b *Boundaries) (*Tracer, error) {
+
+	header := chain.CurrentBlock()
+	stateDB, err := chain.StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	if call.Gas == 0 {
+		call.Gas = 50000000
+	}
+	if call.Value == nil {
+		call.Value = new(big.Int)
+	}
+	msg := &core.Message{
+		From:              call.From,
+		To:                call.To,
+		Value:             call.Value,
+		GasLimit:          call.Gas,
+		GasPrice:          new(big.Int),
+		GasFeeCap:         new(big.Int),
+		GasTipCap:         new(big.Int),
+		Data:              call.Data,
+		SkipAccountChecks: true,
+	}
+
+	tracer := NewTracer(b)
+	blockContext := core.NewEVMBlockContext(header, chain, nil)
+	evm := vm.NewEVM(blockContext, core.NewEVMTxContext(msg), stateDB, chain.Config(), vm.Config{Tracer: tracer, NoBaseFee: true})
+	res, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(math.MaxUint64))
+	if err != nil {
+		return nil, err
+	}
+	if tracer.err != nil {
+		return nil, tracer.err
+	}
+	tracer.CallErr = res.Err
+	if res.Err == vm.ErrExecutionReverted {
+		tracer.CallErr = reverts.Decode(res.Revert())
+	}
+	if len(tracer.States) == 0 {
+		return nil, fmt.Errorf("%w (the call returned %v)", ErrNoState, tracer.CallErr)
+	}
+	return tracer, nil
+}
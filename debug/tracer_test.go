@@ -0,0 +1,125 @@
+package debug_test
+
+import (
+	"errors"
+	"math/big"
+	"os/exec"
+	"testing"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/debug"
+	"github.com/consensys/plonk-solidity/evmrun"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/reverts"
+	"github.com/consensys/plonk-solidity/shadow"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
+)
+
+// TestTracerAgreesWithShadow traces Verify, with the boundaries found in the
+// source map, on verifiers generated without the debug logs, and checks the
+// states against the ones computed by package shadow.
+func TestTracerAgreesWithShadow(t *testing.T) {
+
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip("solc is not in $PATH")
+	}
+
+	for i := range circuits.Examples {
+		example := &circuits.Examples[i]
+		t.Run(example.Name, func(t *testing.T) {
+
+			setup, err := example.Setup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, pi, err := setup.Prove(example.Assignment())
+			if err != nil {
+				t.Fatal(err)
+			}
+			proofBytes := calldata.MarshalProof(*proof)
+			publicInputs := make([]*big.Int, len(pi))
+			for i := range pi {
+				publicInputs[i] = pi[i].BigInt(new(big.Int))
+			}
+
+			for _, mode := range []tmpl.Mode{tmpl.Production, tmpl.Profile} {
+				opts := tmpl.Options{Kind: tmpl.Contract, Mode: mode, AllowTestSRS: true}
+				sources, err := tmpl.GenerateVerifierSources(*setup.VK, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				contracts, err := solc.Compile(sources, solc.Options{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				verifier, err := solc.Find(contracts, "PlonkVerifier")
+				if err != nil {
+					t.Fatal(err)
+				}
+				b, err := debug.NewBoundaries(verifier, opts.Files().Verifier, sources[opts.Files().Verifier])
+				if err != nil {
+					t.Fatal(err)
+				}
+				runner, err := evmrun.Deploy(verifier.Bin)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// the valid proof: a state after each phase
+				expected, err := shadow.Verify(setup.VK, proofBytes, publicInputs)
+				if err != nil {
+					t.Fatal(err)
+				}
+				tracer := debug.NewTracer(b)
+				runner.Tracer = tracer
+				res, err := runner.Verify("Verify", proofBytes, publicInputs)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !res.Accepted {
+					t.Fatalf("%s: the valid proof is rejected: %v", mode, res.Err)
+				}
+				if tracer.Err() != nil {
+					t.Fatalf("%s: %v", mode, tracer.Err())
+				}
+				if len(tracer.States) != len(debug.Phases()) {
+					t.Fatalf("%s: %d states, expected %d", mode, len(tracer.States), len(debug.Phases()))
+				}
+				if d := shadow.Diff(expected, tracer.States); d != nil {
+					t.Errorf("%s: %v", mode, d)
+				}
+				if tracer.Reverted != nil {
+					t.Errorf("%s: the valid proof reverted in %s", mode, tracer.Reverted.Phase)
+				}
+
+				if len(publicInputs) == 0 {
+					continue
+				}
+
+				// a wrong public input: the states up to verify_quotient_poly_eval_at_zeta,
+				// which reverts
+				wrong := append([]*big.Int{}, publicInputs...)
+				wrong[0] = new(big.Int).Add(wrong[0], big.NewInt(1))
+				expected, shadowErr := shadow.Verify(setup.VK, proofBytes, wrong)
+				if !errors.Is(shadowErr, reverts.ErrInvalidProof) {
+					t.Fatalf("shadow returns %v on a wrong public input", shadowErr)
+				}
+				tracer = debug.NewTracer(b)
+				runner.Tracer = tracer
+				if res, err = runner.Verify("Verify", proofBytes, wrong); err != nil {
+					t.Fatal(err)
+				}
+				if res.Accepted || !errors.Is(res.Err, reverts.ErrInvalidProof) {
+					t.Fatalf("%s: expected an invalid proof, got %v", mode, res.Err)
+				}
+				if tracer.Reverted == nil || tracer.Reverted.Phase != debug.PhaseQuotientEval {
+					t.Fatalf("%s: expected a revert in %s, got %+v", mode, debug.PhaseQuotientEval, tracer.Reverted)
+				}
+				if d := shadow.Diff(expected, tracer.States); d != nil {
+					t.Errorf("%s: wrong public input: %v", mode, d)
+				}
+			}
+		})
+	}
+}
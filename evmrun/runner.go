@@ -0,0 +1,147 @@
+// Package evmrun runs a contract on an in-memory EVM (go-ethereum's
+// core/vm/runtime), without a blockchain: the contract is deployed once, then
+// each call executes its calldata directly on the deployed code. Nothing is
+// mined and no transaction is signed, which makes a call to Verify orders of
+// magnitude cheaper than through a simulated backend, for fuzzing and
+// benchmarks.
+package evmrun
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/reverts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// DefaultGasLimit is the gas available to a call, as in debug.TraceCall
+const DefaultGasLimit = 50000000
+
+// ErrReturnedFalse is the error of a call which didn't revert but returned false,
+// e.g. a call to a Verify which doesn't revert on an invalid proof.
+var ErrReturnedFalse = errors.New("the call returned false")
+
+// Result is the outcome of a call.
+type Result struct {
+
+	// Accepted is true if the call didn't fail, and didn't return false
+	Accepted bool
+
+	// ExecutionGas is the gas used by the execution of the call. It doesn't
+	// include the intrinsic gas of a transaction carrying the calldata, see
+	// IntrinsicGas, while the txGas returned by package evmtest, from
+	// eth_estimateGas or from a receipt, does.
+	ExecutionGas uint64
+
+	// RevertData is the data returned by REVERT, nil if the call didn't revert
+	RevertData []byte
+
+	// Err is nil if the call is accepted. Otherwise it is the error of the
+	// verifier decoded with package reverts if the call reverted, the error of
+	// the EVM (e.g. out of gas) if it failed, or ErrReturnedFalse.
+	Err error
+}
+
+// Runner calls a contract deployed on an in-memory EVM. It is not safe for
+// concurrent use, each goroutine should use its own Copy.
+type Runner struct {
+
+	// Address of the deployed contract
+	Address common.Address
+
+	// GasLimit is the gas available to a call, DefaultGasLimit by default
+	GasLimit uint64
+
+	// Tracer, if not nil, traces the calls, e.g. a debug.Tracer
+	Tracer vm.EVMLogger
+
+	state *state.StateDB
+}
+
+// newConfig returns the configuration of the EVM, with the chain config of a
+// simulated backend so that both charge the same gas
+func (r *Runner) newConfig() *runtime.Config {
+	return &runtime.Config{
+		ChainConfig: params.AllEthashProtocolChanges,
+		Origin:      common.HexToAddress("0xc0ffee"),
+		GasLimit:    r.GasLimit,
+		State:       r.state,
+		EVMConfig:   vm.Config{Tracer: r.Tracer, NoBaseFee: true},
+	}
+}
+
+// Deploy runs the creation code of a contract, e.g. the bytecode of a verifier
+// compiled with package solc, possibly followed by the ABI encoded arguments of
+// its constructor, and returns a runner calling the deployed contract.
+func Deploy(creationCode []byte) (*Runner, error) {
+
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		return nil, err
+	}
+	r := &Runner{GasLimit: DefaultGasLimit, state: stateDB}
+
+	_, address, _, err := runtime.Create(creationCode, r.newConfig())
+	if err != nil {
+		return nil, err
+	}
+	r.Address = address
+	return r, nil
+}
+
+// Copy returns a runner calling the same contract on a copy of the state, to be
+// used by another goroutine.
+func (r *Runner) Copy() *Runner {
+	res := *r
+	res.state = r.state.Copy()
+	return &res
+}
+
+// Call calls the contract with input as calldata. The state is left unchanged.
+func (r *Runner) Call(input []byte) Result {
+
+	snapshot := r.state.Snapshot()
+	defer r.state.RevertToSnapshot(snapshot)
+
+	cfg := r.newConfig()
+	ret, leftOverGas, err := runtime.Call(r.Address, input, cfg)
+	res := Result{ExecutionGas: cfg.GasLimit - leftOverGas}
+
+	switch {
+	case err == vm.ErrExecutionReverted:
+		res.RevertData = ret
+		res.Err = reverts.Decode(ret)
+	case err != nil:
+		res.Err = err
+	case len(ret) == 32 && new(big.Int).SetBytes(ret).Sign() == 0:
+		res.Err = ErrReturnedFalse
+	default:
+		res.Accepted = true
+	}
+	return res
+}
+
+// Verify calls method(bytes proof, uint256[] public_inputs) on the contract, e.g.
+// test_verifier_go of TestVerifier.sol, or Verify of a verifier generated as a
+// contract.
+func (r *Runner) Verify(method string, proof []byte, publicInputs []*big.Int) (Result, error) {
+	input, err := calldata.EncodeVerifyCall(method, proof, publicInputs)
+	if err != nil {
+		return Result{}, err
+	}
+	return r.Call(input), nil
+}
+
+// IntrinsicGas returns the gas charged to a transaction carrying data as
+// calldata before any execution: 21000, plus 4 per zero byte and 16 per non zero
+// byte (EIP-2028).
+func IntrinsicGas(data []byte) (uint64, error) {
+	return core.IntrinsicGas(data, nil, false, true, true, true)
+}
@@ -0,0 +1,168 @@
+package evmrun
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"os/exec"
+	"testing"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/reverts"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// returnContract returns the creation code of a contract which returns the word
+// w on every call
+func returnContract(w byte) []byte {
+
+	// mstore8(31, w), return(0, 32)
+	runtime := []byte{
+		byte(vm.PUSH1), w, byte(vm.PUSH1), 31, byte(vm.MSTORE8),
+		byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0, byte(vm.RETURN),
+	}
+	constructor := []byte{
+		byte(vm.PUSH1), byte(len(runtime)), byte(vm.DUP1),
+		byte(vm.PUSH1), 11, byte(vm.PUSH1), 0, byte(vm.CODECOPY),
+		byte(vm.PUSH1), 0, byte(vm.RETURN),
+	}
+	return append(constructor, runtime...)
+}
+
+// the gas of a call to returnContract: 4 PUSH1, MSTORE8 and the expansion of the
+// memory to one word
+const returnContractGas = 4*3 + 3 + 3
+
+func TestCall(t *testing.T) {
+
+	// ExecutionGas is the gas of the execution only, whatever the calldata
+	r, err := Deploy(returnContract(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := r.Call([]byte{1, 2, 3})
+	if res.Accepted || res.Err != ErrReturnedFalse || res.RevertData != nil {
+		t.Errorf("returning false: %+v", res)
+	}
+	if res.ExecutionGas != returnContractGas {
+		t.Errorf("returning false: %d gas used, expected %d", res.ExecutionGas, returnContractGas)
+	}
+
+	r, err = Deploy(returnContract(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res = r.Call(nil); !res.Accepted || res.Err != nil {
+		t.Errorf("returning true: %+v", res)
+	}
+	if res.ExecutionGas != returnContractGas {
+		t.Errorf("returning true: %d gas used, expected %d", res.ExecutionGas, returnContractGas)
+	}
+
+	// out of gas, all the gas is used
+	r.GasLimit = 5
+	if res = r.Call(nil); res.Accepted || !errors.Is(res.Err, vm.ErrOutOfGas) || res.ExecutionGas != 5 {
+		t.Errorf("out of gas: %+v", res)
+	}
+}
+
+// TestVerify deploys the verifier of each example, and checks the result of
+// Verify on a valid proof and on invalid ones.
+func TestVerify(t *testing.T) {
+
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip("solc is not in $PATH")
+	}
+
+	for i := range circuits.Examples {
+		example := &circuits.Examples[i]
+		t.Run(example.Name, func(t *testing.T) {
+
+			setup, err := example.Setup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, pi, err := setup.Prove(example.Assignment())
+			if err != nil {
+				t.Fatal(err)
+			}
+			proofBytes := calldata.MarshalProof(*proof)
+			publicInputs := make([]*big.Int, len(pi))
+			for i := range pi {
+				publicInputs[i] = pi[i].BigInt(new(big.Int))
+			}
+
+			opts := tmpl.Options{Kind: tmpl.Contract, AllowTestSRS: true}
+			sources, err := tmpl.GenerateVerifierSources(*setup.VK, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			contracts, err := solc.Compile(sources, solc.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifier, err := solc.Find(contracts, opts.Contracts().Verifier)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := Deploy(verifier.Bin)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			valid, err := r.Verify("Verify", proofBytes, publicInputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !valid.Accepted || valid.Err != nil || valid.RevertData != nil {
+				t.Fatalf("the valid proof is rejected: %+v", valid)
+			}
+			// at least the pairing, at most the limit
+			if valid.ExecutionGas < 45000+2*34000 || valid.ExecutionGas >= r.GasLimit {
+				t.Errorf("the valid proof uses %d gas", valid.ExecutionGas)
+			}
+			// the state is left unchanged, the same call costs the same gas
+			if again, _ := r.Verify("Verify", proofBytes, publicInputs); again.ExecutionGas != valid.ExecutionGas {
+				t.Errorf("the same call uses %d gas, then %d", valid.ExecutionGas, again.ExecutionGas)
+			}
+			if copied, _ := r.Copy().Verify("Verify", proofBytes, publicInputs); copied.ExecutionGas != valid.ExecutionGas {
+				t.Errorf("a copy uses %d gas, the runner %d", copied.ExecutionGas, valid.ExecutionGas)
+			}
+
+			// a proof of the wrong size is rejected before any computation
+			res, err := r.Verify("Verify", proofBytes[:len(proofBytes)-32], publicInputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var verr *reverts.VerifierError
+			if res.Accepted || !errors.As(res.Err, &verr) || verr.Spec.Name != "WrongProofSize" {
+				t.Fatalf("truncated proof: expected WrongProofSize, got %v", res.Err)
+			}
+			if sel := verr.Spec.Selector(); !bytes.HasPrefix(res.RevertData, sel[:]) {
+				t.Errorf("truncated proof: revert data %x", res.RevertData)
+			}
+			if res.ExecutionGas == 0 || res.ExecutionGas >= valid.ExecutionGas/10 {
+				t.Errorf("truncated proof: %d gas used, %d for the valid proof", res.ExecutionGas, valid.ExecutionGas)
+			}
+
+			// a wrong public input is found by the quotient check, before the pairing
+			if len(publicInputs) == 0 {
+				return
+			}
+			wrong := append([]*big.Int{}, publicInputs...)
+			wrong[0] = new(big.Int).Add(wrong[0], big.NewInt(1))
+			if res, err = r.Verify("Verify", proofBytes, wrong); err != nil {
+				t.Fatal(err)
+			}
+			if res.Accepted || !errors.Is(res.Err, reverts.ErrInvalidProof) || res.RevertData == nil {
+				t.Errorf("wrong public input: expected an invalid proof, got %+v", res)
+			}
+			if res.ExecutionGas == 0 || res.ExecutionGas >= valid.ExecutionGas {
+				t.Errorf("wrong public input: %d gas used, %d for the valid proof", res.ExecutionGas, valid.ExecutionGas)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+// Package evmtest deploys a verifier on a simulated backend (go-ethereum's
+// backends.SimulatedBackend) and calls it from Go tests: the verifier is called
+// with eth_call, or sent a transaction which is mined.
+//
+// A test of a circuit against its Solidity verifier reads:
+//
+//	b, err := evmtest.NewBackend()
+//	v, err := b.Deploy(ctx, verifierBin)
+//	ok, txGas, err := v.Verify(ctx, proof, publicInputs)
+//
+// Unlike package evmrun, every call goes through the JSON-RPC semantics of a
+// node: gas estimation, signed transactions, receipts.
+package evmtest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/evmrun"
+	"github.com/consensys/plonk-solidity/reverts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Default configuration of a backend
+const (
+	DefaultChainID       = 1337
+	DefaultGasLimit      = 500000
+	DefaultBlockGasLimit = 14712388
+	DefaultMethod        = "Verify"
+)
+
+// DefaultBalance is the balance of the account of a backend, 10 eth in wei
+var DefaultBalance = new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18))
+
+type config struct {
+	chainID       *big.Int
+	balance       *big.Int
+	gasLimit      uint64
+	blockGasLimit uint64
+	privateKey    *ecdsa.PrivateKey
+	method        string
+	transact      bool
+}
+
+// Option configures a Backend, or the Verifier deployed by Backend.Deploy.
+type Option func(*config)
+
+// WithChainID sets the chain ID of the backend, DefaultChainID by default.
+func WithChainID(chainID *big.Int) Option {
+	return func(c *config) { c.chainID = chainID }
+}
+
+// WithBalance sets the balance of the account sending the transactions,
+// DefaultBalance by default.
+func WithBalance(balance *big.Int) Option {
+	return func(c *config) { c.balance = balance }
+}
+
+// WithGasLimit sets the gas limit of the transactions, and of the calls,
+// DefaultGasLimit by default.
+func WithGasLimit(gasLimit uint64) Option {
+	return func(c *config) { c.gasLimit = gasLimit }
+}
+
+// WithBlockGasLimit sets the gas limit of the blocks, DefaultBlockGasLimit by
+// default.
+func WithBlockGasLimit(gasLimit uint64) Option {
+	return func(c *config) { c.blockGasLimit = gasLimit }
+}
+
+// WithPrivateKey sets the key of the account sending the transactions, a new key
+// is generated by default.
+func WithPrivateKey(privateKey *ecdsa.PrivateKey) Option {
+	return func(c *config) { c.privateKey = privateKey }
+}
+
+// WithMethod sets the method called by Verifier.Verify, with the signature
+// method(bytes,uint256[]). It is DefaultMethod by default, the entry point of a
+// verifier generated as a contract; test_verifier_go calls the verifier through
+// TestVerifier.sol.
+func WithMethod(method string) Option {
+	return func(c *config) { c.method = method }
+}
+
+// WithTransactions makes Verifier.Verify send a transaction instead of an
+// eth_call.
+func WithTransactions() Option {
+	return func(c *config) { c.transact = true }
+}
+
+// Backend is a simulated backend with a funded account.
+type Backend struct {
+	Client *backends.SimulatedBackend
+
+	// From is the address of the account sending the transactions
+	From common.Address
+
+	config
+}
+
+// NewBackend returns a simulated backend whose genesis funds a single account.
+// The options passed to NewBackend are the default options of the verifiers it
+// deploys.
+func NewBackend(opts ...Option) (*Backend, error) {
+
+	c := config{
+		chainID:       big.NewInt(DefaultChainID),
+		balance:       DefaultBalance,
+		gasLimit:      DefaultGasLimit,
+		blockGasLimit: DefaultBlockGasLimit,
+		method:        DefaultMethod,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.privateKey == nil {
+		var err error
+		if c.privateKey, err = crypto.GenerateKey(); err != nil {
+			return nil, err
+		}
+	}
+
+	from := crypto.PubkeyToAddress(c.privateKey.PublicKey)
+	genesisAlloc := core.GenesisAlloc{from: {Balance: c.balance}}
+	client := backends.NewSimulatedBackend(genesisAlloc, c.blockGasLimit)
+
+	return &Backend{Client: client, From: from, config: c}, nil
+}
+
+// Close stops the backend.
+func (b *Backend) Close() error {
+	return b.Client.Close()
+}
+
+// TransactOpts returns the options of the next transaction of the account: its
+// pending nonce, the suggested gas price and the gas limit of the backend.
+func (b *Backend) TransactOpts(ctx context.Context) (*bind.TransactOpts, error) {
+
+	auth, err := bind.NewKeyedTransactorWithChainID(b.privateKey, b.chainID)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := b.Client.PendingNonceAt(ctx, b.From)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := b.Client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	auth.Context = ctx
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+	auth.Value = big.NewInt(0)
+	auth.GasLimit = b.gasLimit
+	auth.GasPrice = gasPrice
+	return auth, nil
+}
+
+// Verifier is a contract deployed on a Backend, whose method(bytes,uint256[])
+// verifies a proof.
+type Verifier struct {
+	Address common.Address
+
+	backend *Backend
+	config
+}
+
+// Deploy deploys a contract from its creation code, e.g. the bytecode of a
+// verifier generated as a contract and compiled with package solc, or of
+// TestVerifier. The deployment is mined. opts override the options of the
+// backend for this verifier.
+func (b *Backend) Deploy(ctx context.Context, creationCode []byte, opts ...Option) (*Verifier, error) {
+
+	auth, err := b.TransactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the gas limit of a call to Verify may be too low to deploy the verifier
+	auth.GasLimit = 0
+
+	// without ABI, the creation code is sent as is
+	address, tx, _, err := bind.DeployContract(auth, abi.ABI{}, creationCode, b.Client)
+	if err != nil {
+		return nil, err
+	}
+	b.Client.Commit()
+	receipt, err := b.Client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, errors.New("the deployment of the verifier failed")
+	}
+
+	v := &Verifier{Address: address, backend: b, config: b.config}
+	for _, opt := range opts {
+		opt(&v.config)
+	}
+	return v, nil
+}
+
+// Verify verifies proof and publicInputs with an eth_call, or with a transaction
+// if the verifier is configured WithTransactions, see Call and Transact.
+func (v *Verifier) Verify(ctx context.Context, proof []byte, publicInputs []*big.Int) (ok bool, txGas uint64, err error) {
+	if v.transact {
+		return v.Transact(ctx, proof, publicInputs)
+	}
+	return v.Call(ctx, proof, publicInputs)
+}
+
+// Call verifies proof and publicInputs with an eth_call on the pending state.
+//
+// ok is true if the verifier accepts the proof; txGas is then the gas estimated
+// by eth_estimateGas for a transaction doing the same call, intrinsic gas
+// included. It is the lowest gas limit for which the transaction succeeds, a bit
+// more than the gas used by the transaction because of the gas a call keeps back
+// (EIP-150).
+// If the verifier rejects the proof, err is its error decoded with package
+// reverts, or evmrun.ErrReturnedFalse if Verify returned false. Other errors are
+// failures of the backend.
+func (v *Verifier) Call(ctx context.Context, proof []byte, publicInputs []*big.Int) (ok bool, txGas uint64, err error) {
+	input, err := calldata.EncodeVerifyCall(v.method, proof, publicInputs)
+	if err != nil {
+		return false, 0, err
+	}
+	return v.CallRaw(ctx, input)
+}
+
+// CallRaw is Call with the calldata input, e.g. the selector of test_verifier()
+// of TestVerifier.sol.
+func (v *Verifier) CallRaw(ctx context.Context, input []byte) (ok bool, txGas uint64, err error) {
+
+	msg := ethereum.CallMsg{From: v.backend.From, To: &v.Address, Gas: v.gasLimit, Data: input}
+	ret, err := v.backend.Client.PendingCallContract(ctx, msg)
+	if err != nil {
+		return false, 0, reverts.FromCallError(err)
+	}
+	if len(ret) == 32 && new(big.Int).SetBytes(ret).Sign() == 0 {
+		return false, 0, evmrun.ErrReturnedFalse
+	}
+	txGas, err = v.backend.Client.EstimateGas(ctx, msg)
+	if err != nil {
+		return false, 0, reverts.FromCallError(err)
+	}
+	return true, txGas, nil
+}
+
+// Transact verifies proof and publicInputs with a transaction, which is mined.
+//
+// txGas is the gas used by the transaction, from its receipt, intrinsic gas
+// included: evmrun.Result.ExecutionGas plus evmrun.IntrinsicGas of the calldata. If the transaction reverts, ok is false and err is the error of the
+// verifier decoded with package reverts. The value returned by Verify is not
+// part of the receipt: a Verify returning false without reverting is reported as
+// accepted, which the verifiers of this repository never do.
+func (v *Verifier) Transact(ctx context.Context, proof []byte, publicInputs []*big.Int) (ok bool, txGas uint64, err error) {
+	input, err := calldata.EncodeVerifyCall(v.method, proof, publicInputs)
+	if err != nil {
+		return false, 0, err
+	}
+	return v.TransactRaw(ctx, input)
+}
+
+// TransactRaw is Transact with the calldata input.
+func (v *Verifier) TransactRaw(ctx context.Context, input []byte) (ok bool, txGas uint64, err error) {
+
+	auth, err := v.backend.TransactOpts(ctx)
+	if err != nil {
+		return false, 0, err
+	}
+	auth.GasLimit = v.gasLimit
+
+	contract := bind.NewBoundContract(v.Address, abi.ABI{}, v.backend.Client, v.backend.Client, v.backend.Client)
+	tx, err := contract.RawTransact(auth, input)
+	if err != nil {
+		return false, 0, err
+	}
+	v.backend.Client.Commit()
+
+	receipt, err := v.backend.Client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return false, 0, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return false, receipt.GasUsed, reverts.FromReceipt(ctx, v.backend.Client, v.backend.From, tx, receipt)
+	}
+	return true, receipt.GasUsed, nil
+}
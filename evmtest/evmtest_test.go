@@ -0,0 +1,131 @@
+package evmtest
+
+import (
+	"context"
+	"math/big"
+	"os/exec"
+	"testing"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/evmrun"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// returnTrueContract returns the creation code of a contract which returns true
+// on every call, without calling another contract
+func returnTrueContract() []byte {
+	runtime := []byte{
+		byte(vm.PUSH1), 1, byte(vm.PUSH1), 31, byte(vm.MSTORE8),
+		byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0, byte(vm.RETURN),
+	}
+	constructor := []byte{
+		byte(vm.PUSH1), byte(len(runtime)), byte(vm.DUP1),
+		byte(vm.PUSH1), 11, byte(vm.PUSH1), 0, byte(vm.CODECOPY),
+		byte(vm.PUSH1), 0, byte(vm.RETURN),
+	}
+	return append(constructor, runtime...)
+}
+
+// compareGas makes the same call with evmtest and evmrun, and checks that the gas
+// of the transaction is the gas of the execution plus the intrinsic gas. It
+// returns the gas used by the transaction and the gas estimated for it.
+func compareGas(t *testing.T, bin, input []byte) (txGas, estimatedGas uint64) {
+	t.Helper()
+
+	ctx := context.Background()
+	backend, err := NewBackend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	v, err := backend.Deploy(ctx, bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := evmrun.Deploy(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, estimatedGas, err := v.CallRaw(ctx, input)
+	if !ok {
+		t.Fatalf("call: %v", err)
+	}
+	ok, txGas, err = v.TransactRaw(ctx, input)
+	if !ok {
+		t.Fatalf("transaction: %v", err)
+	}
+	res := r.Call(input)
+	if !res.Accepted {
+		t.Fatalf("evmrun: %v", res.Err)
+	}
+	intrinsicGas, err := evmrun.IntrinsicGas(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if txGas != res.ExecutionGas+intrinsicGas {
+		t.Errorf("the transaction uses %d gas, evmrun %d + %d intrinsic", txGas, res.ExecutionGas, intrinsicGas)
+	}
+	if estimatedGas < txGas {
+		t.Errorf("%d gas estimated, the transaction uses %d", estimatedGas, txGas)
+	}
+	return txGas, estimatedGas
+}
+
+// TestGasAgreesWithEvmrun checks the gas returned by Call and Transact against
+// the gas of the same call with evmrun.
+func TestGasAgreesWithEvmrun(t *testing.T) {
+
+	// without a call, no gas is kept back and the estimate is exact
+	input := []byte{0, 1, 2, 3, 0}
+	if txGas, estimatedGas := compareGas(t, returnTrueContract(), input); estimatedGas != txGas {
+		t.Errorf("%d gas estimated, the transaction uses %d", estimatedGas, txGas)
+	}
+
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip("solc is not in $PATH")
+	}
+
+	example := &circuits.Examples[0]
+	setup, err := example.Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, pi, err := setup.Prove(example.Assignment())
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicInputs := make([]*big.Int, len(pi))
+	for i := range pi {
+		publicInputs[i] = pi[i].BigInt(new(big.Int))
+	}
+	if input, err = calldata.EncodeVerifyCall("Verify", calldata.MarshalProof(*proof), publicInputs); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := tmpl.Options{Kind: tmpl.Contract, AllowTestSRS: true}
+	sources, err := tmpl.GenerateVerifierSources(*setup.VK, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contracts, err := solc.Compile(sources, solc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := solc.Find(contracts, opts.Contracts().Verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify calls the precompiles, which keep back 1/64 of the gas left: the
+	// estimate exceeds the gas used by at most 1/63 of the largest call, the
+	// pairing
+	txGas, estimatedGas := compareGas(t, verifier.Bin, input)
+	if estimatedGas > txGas+txGas/63 {
+		t.Errorf("%d gas estimated, the transaction uses %d", estimatedGas, txGas)
+	}
+}
@@ -0,0 +1,111 @@
+package gasmodel
+
+import (
+	"math/big"
+)
+
+// intrinsic gas of a transaction, and the prices of EIP-7623
+const (
+	txGas                  = 21000
+	floorPerToken          = 10
+	nonZeroTokens          = 4
+	bedrockSignatureBytes  = 68
+	ecotoneScalarPrecision = 1000000
+)
+
+// TxGas is the gas of a transaction calling Verify before Prague: the intrinsic
+// gas, the calldata priced with EIP-2028, and the execution.
+func (e *Estimate) TxGas() uint64 {
+	return txGas + e.Calldata.Gas() + e.Gas
+}
+
+// TxGasFloor is the gas of a transaction calling Verify since Prague (EIP-7623):
+// TxGas, unless the calldata floor of 10 gas per token is higher.
+func (e *Estimate) TxGasFloor() uint64 {
+	floor := txGas + floorPerToken*e.Calldata.tokens()
+	if standard := e.TxGas(); standard > floor {
+		return standard
+	}
+	return floor
+}
+
+// tokens counts the calldata tokens of EIP-7623: 1 per zero byte, 4 per non
+// zero byte. EIP-2028 prices a token 4 gas.
+func (c Calldata) tokens() uint64 {
+	return uint64(c.ZeroBytes + nonZeroTokens*(c.Bytes-c.ZeroBytes))
+}
+
+// DataFee is a model of the fee, in wei, paid to a rollup to post the calldata
+// of Verify on L1. It comes on top of the L2 execution fee, Estimate.Gas times
+// the L2 gas price.
+type DataFee interface {
+	DataFee(c Calldata) *big.Int
+}
+
+// L1 is the fee of the calldata of a transaction on L1: its EIP-2028 gas times
+// GasPrice. It is the DataFee of a rollup posting its batches as calldata
+// without compression.
+type L1 struct {
+	GasPrice *big.Int
+}
+
+// DataFee implements DataFee.
+func (m L1) DataFee(c Calldata) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(c.Gas()), m.GasPrice)
+}
+
+// Bedrock is the L1 data fee of the OP Stack before Ecotone:
+//
+//	(calldata gas + 68*16 + Overhead) * L1BaseFee * Scalar / 10⁶
+//
+// where 68 bytes account for the signature of the transaction.
+type Bedrock struct {
+	L1BaseFee *big.Int
+	Overhead  uint64
+	Scalar    uint64
+}
+
+// DataFee implements DataFee.
+func (m Bedrock) DataFee(c Calldata) *big.Int {
+	gas := c.Gas() + 16*bedrockSignatureBytes + m.Overhead
+	res := new(big.Int).SetUint64(gas)
+	res.Mul(res, m.L1BaseFee)
+	res.Mul(res, new(big.Int).SetUint64(m.Scalar))
+	return res.Div(res, big.NewInt(ecotoneScalarPrecision))
+}
+
+// Ecotone is the L1 data fee of the OP Stack since Ecotone, when batches are
+// posted in blobs:
+//
+//	(calldata gas + 68*16) * (16 * BaseFeeScalar * L1BaseFee + BlobBaseFeeScalar * BlobBaseFee) / (16 * 10⁶)
+type Ecotone struct {
+	L1BaseFee         *big.Int
+	BlobBaseFee       *big.Int
+	BaseFeeScalar     uint64
+	BlobBaseFeeScalar uint64
+}
+
+// DataFee implements DataFee.
+func (m Ecotone) DataFee(c Calldata) *big.Int {
+	gas := c.Gas() + 16*bedrockSignatureBytes
+
+	weighted := new(big.Int).Mul(m.L1BaseFee, new(big.Int).SetUint64(16*m.BaseFeeScalar))
+	blob := new(big.Int).Mul(m.BlobBaseFee, new(big.Int).SetUint64(m.BlobBaseFeeScalar))
+	weighted.Add(weighted, blob)
+
+	res := new(big.Int).SetUint64(gas)
+	res.Mul(res, weighted)
+	return res.Div(res, big.NewInt(16*ecotoneScalarPrecision))
+}
+
+// Blob is the fee of the calldata posted in EIP-4844 blobs, at one blob gas per
+// byte, assuming the rollup fills its blobs and doesn't compress: a proof is
+// made of uniformly random field elements, which don't compress.
+type Blob struct {
+	BlobBaseFee *big.Int
+}
+
+// DataFee implements DataFee.
+func (m Blob) DataFee(c Calldata) *big.Int {
+	return new(big.Int).Mul(big.NewInt(int64(c.Bytes)), m.BlobBaseFee)
+}
@@ -0,0 +1,78 @@
+package gasmodel
+
+import (
+	"math/big"
+	"testing"
+)
+
+// calldata of 1000 bytes of which 120 are zero: 4*120 + 16*880 = 14560 gas, and
+// 120 + 4*880 = 3640 tokens
+var testCalldata = Calldata{Bytes: 1000, ZeroBytes: 120}
+
+func TestCalldataGas(t *testing.T) {
+
+	if gas := testCalldata.Gas(); gas != 14560 {
+		t.Fatalf("calldata gas %d, expected 14560", gas)
+	}
+	if tokens := testCalldata.tokens(); tokens != 3640 {
+		t.Fatalf("%d tokens, expected 3640", tokens)
+	}
+
+	// selector and 2 offsets, 1 commitment: a proof of 26*32 + 32 + 64 = 928
+	// bytes and its length, 2 public inputs and their length
+	c := EstimateCalldata(1, 2)
+	if c.Bytes != 4+64+32+928+32+64 || c.ZeroBytes != 120 {
+		t.Fatalf("calldata %+v, expected 1124 bytes of which 120 are zero", c)
+	}
+}
+
+func TestTxGasFloor(t *testing.T) {
+
+	// the floor is 21000 + 10*3640 = 57400
+	tests := []struct {
+		name  string
+		gas   uint64
+		txGas uint64
+		floor uint64
+	}{
+		// 21000 + 14560 + 300000
+		{"execution above the floor", 300000, 335560, 335560},
+		// 21000 + 14560 + 1000 < 57400
+		{"calldata floor", 1000, 36560, 57400},
+	}
+	for _, tt := range tests {
+		e := Estimate{Gas: tt.gas, Calldata: testCalldata}
+		if gas := e.TxGas(); gas != tt.txGas {
+			t.Errorf("%s: TxGas %d, expected %d", tt.name, gas, tt.txGas)
+		}
+		if gas := e.TxGasFloor(); gas != tt.floor {
+			t.Errorf("%s: TxGasFloor %d, expected %d", tt.name, gas, tt.floor)
+		}
+	}
+}
+
+func TestDataFee(t *testing.T) {
+
+	gwei := big.NewInt(1e9)
+	tenGwei := big.NewInt(1e10)
+
+	tests := []struct {
+		name     string
+		model    DataFee
+		expected string
+	}{
+		// 14560 * 10¹⁰
+		{"L1", L1{GasPrice: tenGwei}, "145600000000000"},
+		// (14560 + 68*16 + 188) * 10¹⁰ * 684000 / 10⁶
+		{"Bedrock", Bedrock{L1BaseFee: tenGwei, Overhead: 188, Scalar: 684000}, "108318240000000"},
+		// (14560 + 68*16) * (16*1368*10¹⁰ + 810949*1) / (16*10⁶)
+		{"Ecotone", Ecotone{L1BaseFee: tenGwei, BlobBaseFee: big.NewInt(1), BaseFeeScalar: 1368, BlobBaseFeeScalar: 810949}, "214064640793"},
+		// 1000 * 10⁹
+		{"Blob", Blob{BlobBaseFee: gwei}, "1000000000000"},
+	}
+	for _, tt := range tests {
+		if fee := tt.model.DataFee(testCalldata); fee.String() != tt.expected {
+			t.Errorf("%s: fee %s, expected %s", tt.name, fee, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package gasmodel
+
+import (
+	"errors"
+	"math"
+
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+)
+
+// Measure is the gas used by Verify on a valid proof, for the verifier of VK.
+type Measure struct {
+	VK             *bn254plonk.VerifyingKey
+	NbPublicInputs int
+	Gas            uint64
+}
+
+// ErrUnderdetermined is returned by Fit when the measures don't vary enough to
+// determine the three coefficients, e.g. when they all have the same number of
+// commitments.
+var ErrUnderdetermined = errors.New("the measures don't determine the coefficients")
+
+// Fit returns the coefficients minimising the squared error of the model on the
+// measures: the gas of the precompiles, which the model prices exactly, is
+// subtracted from each measure, and the rest is fitted by least squares as an
+// affine function of the number of public inputs and of commitments.
+func Fit(measures []Measure) (Coefficients, error) {
+
+	// normal equations AᵀA x = Aᵀb, where the rows of A are (1, public inputs,
+	// commitments) and b is the execution gas
+	var ata [3][3]float64
+	var atb [3]float64
+	for _, m := range measures {
+		precompileGas := uint64(0)
+		for _, p := range countPrecompiles(m.VK, m.NbPublicInputs) {
+			precompileGas += p.Gas
+		}
+		if m.Gas < precompileGas {
+			return Coefficients{}, errors.New("a measure uses less gas than its precompiles")
+		}
+		row := [3]float64{1, float64(m.NbPublicInputs), float64(len(m.VK.CommitmentConstraintIndexes))}
+		b := float64(m.Gas - precompileGas)
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				ata[i][j] += row[i] * row[j]
+			}
+			atb[i] += row[i] * b
+		}
+	}
+
+	x, ok := solve3(ata, atb)
+	if !ok {
+		return Coefficients{}, ErrUnderdetermined
+	}
+	round := func(v float64) uint64 {
+		if v < 0 {
+			return 0
+		}
+		return uint64(math.Round(v))
+	}
+	return Coefficients{
+		Base:           round(x[0]),
+		PerPublicInput: round(x[1]),
+		PerCommitment:  round(x[2]),
+	}, nil
+}
+
+// solve3 solves a x = b by Gaussian elimination with partial pivoting, ok is
+// false if a is singular
+func solve3(a [3][3]float64, b [3]float64) (x [3]float64, ok bool) {
+	const epsilon = 1e-9
+	for col := 0; col < 3; col++ {
+		pivot := col
+		for row := col + 1; row < 3; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < epsilon {
+			return x, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+		for row := col + 1; row < 3; row++ {
+			f := a[row][col] / a[col][col]
+			for k := col; k < 3; k++ {
+				a[row][k] -= f * a[col][k]
+			}
+			b[row] -= f * b[col]
+		}
+	}
+	for row := 2; row >= 0; row-- {
+		v := b[row]
+		for k := row + 1; k < 3; k++ {
+			v -= a[row][k] * x[k]
+		}
+		x[row] = v / a[row][row]
+	}
+	return x, true
+}
+
+// Error is the relative error of the estimate of m, in percent.
+func (c Coefficients) Error(m Measure) float64 {
+	e := EstimateGasWith(m.VK, m.NbPublicInputs, c)
+	return 100 * (float64(e.Gas) - float64(m.Gas)) / float64(m.Gas)
+}
@@ -0,0 +1,242 @@
+// Package gasmodel predicts the cost of a call to Verify from the verifying key,
+// without running anything: the gas of its execution, the size of its calldata
+// and the fees to post the calldata on L1 or on a rollup.
+//
+// The precompile calls of Verify are counted from Verifier.sol and priced
+// exactly. The rest of the execution (loops, memory, ABI decoding, hash_fr) is
+// an affine function of the number of public inputs and of BSB22 commitments,
+// whose coefficients are fitted on measures, see Fit and cmd/gasbench.
+package gasmodel
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/plonk-solidity/calldata"
+)
+
+// prices of the precompiles, since Istanbul (EIP-1108) and Berlin (EIP-2565)
+const (
+	sha256Base     = 60
+	sha256PerWord  = 12
+	modexpMin      = 200
+	ecAddGas       = 150
+	ecMulGas       = 6000
+	pairingBase    = 45000
+	pairingPerPair = 34000
+
+	// a precompile is always warm (EIP-2929), calling it costs 100
+	warmCallGas = 100
+)
+
+// Precompile is the use of a precompile by Verify.
+type Precompile struct {
+	Name  string
+	Calls int
+
+	// Gas spent in the precompile and in the call opcodes
+	Gas uint64
+}
+
+func (p *Precompile) call(gas uint64) {
+	p.Calls++
+	p.Gas += gas + warmCallGas
+}
+
+// Coefficients of the execution gas of Verify which is not spent in precompiles.
+type Coefficients struct {
+	Base           uint64
+	PerPublicInput uint64
+	PerCommitment  uint64
+}
+
+// DefaultCoefficients are estimates from the opcodes of the loops of
+// Verifier.sol: a public input is checked, copied to the transcript, and goes
+// through the lagrange and batch inversion loops; a commitment is dominated by
+// the byte loops of hash_fr. Refit them with cmd/gasbench -fit for a given solc.
+var DefaultCoefficients = Coefficients{
+	Base:           30000,
+	PerPublicInput: 350,
+	PerCommitment:  20000,
+}
+
+// Estimate is the predicted cost of a call to Verify.
+type Estimate struct {
+
+	// Precompiles are the uses of sha256, modexp, ecAdd, ecMul and ecPairing
+	Precompiles []Precompile
+
+	// PrecompileGas is the sum of the gas of Precompiles, ExecutionGas the rest
+	// of the gas used by Verify, and Gas their sum. Gas compares to
+	// evmrun.Result.ExecutionGas, precompiles included: the intrinsic gas of a
+	// transaction is not included.
+	PrecompileGas uint64
+	ExecutionGas  uint64
+	Gas           uint64
+
+	// Calldata is the size of the calldata of Verify(bytes,uint256[]), selector
+	// included
+	Calldata Calldata
+}
+
+// EstimateGas predicts the cost of a call to Verify with nbPublicInputs public
+// inputs, for the verifier of vk, with DefaultCoefficients. Verify only accepts
+// vk.NbPublicVariables public inputs, another count estimates the cost of a
+// variant of the circuit.
+func EstimateGas(vk *bn254plonk.VerifyingKey, nbPublicInputs int) Estimate {
+	return EstimateGasWith(vk, nbPublicInputs, DefaultCoefficients)
+}
+
+// EstimateGasWith is EstimateGas with other coefficients.
+func EstimateGasWith(vk *bn254plonk.VerifyingKey, nbPublicInputs int, c Coefficients) Estimate {
+
+	nbCommitments := len(vk.CommitmentConstraintIndexes)
+	precompiles := countPrecompiles(vk, nbPublicInputs)
+
+	var res Estimate
+	res.Precompiles = precompiles
+	for _, p := range precompiles {
+		res.PrecompileGas += p.Gas
+	}
+	res.ExecutionGas = c.Base + c.PerPublicInput*uint64(nbPublicInputs) + c.PerCommitment*uint64(nbCommitments)
+	res.Gas = res.PrecompileGas + res.ExecutionGas
+	res.Calldata = EstimateCalldata(nbCommitments, nbPublicInputs)
+
+	return res
+}
+
+// countPrecompiles follows the precompile calls of Verify, in the order of the
+// phases of the verification
+func countPrecompiles(vk *bn254plonk.VerifyingKey, nbPublicInputs int) []Precompile {
+
+	sha256 := Precompile{Name: "sha256"}
+	modexp := Precompile{Name: "modexp"}
+	ecAdd := Precompile{Name: "ecAdd"}
+	ecMul := Precompile{Name: "ecMul"}
+	pairing := Precompile{Name: "ecPairing"}
+
+	c := len(vk.CommitmentConstraintIndexes)
+	n := new(big.Int).SetUint64(vk.Size)
+	rMinusTwo := new(big.Int).Sub(fr.Modulus(), big.NewInt(2))
+
+	// derive_gamma_beta_alpha_zeta: "gamma" || vk || public inputs || Bsb22
+	// commitments || L, R, O, then beta, alpha, zeta
+	sha256.call(sha256Gas(0x2c5 + 0x20*nbPublicInputs + 0x40*c))
+	sha256.call(sha256Gas(0x24))
+	sha256.call(sha256Gas(0x65))
+	sha256.call(sha256Gas(0xe4))
+
+	// compute_pi: ζⁿ in batch_compute_lagranges_at_z, the inversion in
+	// batch_invert, ζⁿ again, then for each commitment hash_fr (3 sha256 in
+	// expand_msg) and compute_ith_lagrange_at_z (ωⁱ, ζⁿ, inversion)
+	modexp.call(modexpGas(n))
+	modexp.call(modexpGas(rMinusTwo))
+	modexp.call(modexpGas(n))
+	for i := 0; i < c; i++ {
+		sha256.call(sha256Gas(143))
+		sha256.call(sha256Gas(45))
+		sha256.call(sha256Gas(45))
+		index := new(big.Int).SetUint64(vk.CommitmentConstraintIndexes[i] + uint64(nbPublicInputs))
+		modexp.call(modexpGas(index))
+		modexp.call(modexpGas(n))
+		modexp.call(modexpGas(rMinusTwo))
+	}
+
+	// compute_alpha_square_lagrange_0: ζⁿ and an inversion
+	modexp.call(modexpGas(n))
+	modexp.call(modexpGas(rMinusTwo))
+
+	// fold_h: ζⁿ⁺², 2 point_mul and 2 point_add
+	modexp.call(modexpGas(new(big.Int).Add(n, big.NewInt(2))))
+	for i := 0; i < 2; i++ {
+		ecMul.call(ecMulGas)
+		ecAdd.call(ecAddGas)
+	}
+
+	// compute_commitment_linearised_polynomial_ec: a point_mul, a point_add, and a
+	// point_acc_mul for Qr, Qm, Qo, S3, Z and each Bsb22 commitment
+	ecMul.call(ecMulGas)
+	ecAdd.call(ecAddGas)
+	for i := 0; i < 5+c; i++ {
+		ecMul.call(ecMulGas)
+		ecAdd.call(ecAddGas)
+	}
+
+	// compute_gamma_kzg: "gamma" || ζ || 7 points || Qcp || 7 openings || Qcp(ζ)
+	sha256.call(sha256Gas(5 + 0x20*(0x16+3*c)))
+
+	// fold_state: a point_acc_mul for linearised polynomial, L, R, O, S1, S2 and
+	// each Bsb22 commitment
+	for i := 0; i < 6+c; i++ {
+		ecMul.call(ecMulGas)
+		ecAdd.call(ecAddGas)
+	}
+
+	// batch_verify_multi_points: 3 point_acc_mul, [folded evals]₁, a point_mul,
+	// 2 point_add, and the pairing of 2 pairs
+	for i := 0; i < 3; i++ {
+		ecMul.call(ecMulGas)
+		ecAdd.call(ecAddGas)
+	}
+	ecMul.call(ecMulGas)
+	ecMul.call(ecMulGas)
+	ecAdd.call(ecAddGas)
+	ecAdd.call(ecAddGas)
+	pairing.call(pairingBase + 2*pairingPerPair)
+
+	return []Precompile{sha256, modexp, ecAdd, ecMul, pairing}
+}
+
+// sha256Gas is the price of sha256 on size bytes
+func sha256Gas(size int) uint64 {
+	return sha256Base + sha256PerWord*uint64((size+31)/32)
+}
+
+// modexpGas is the price of modexp (EIP-2565) with a base, an exponent and a
+// modulus of 32 bytes
+func modexpGas(exponent *big.Int) uint64 {
+	const multiplicationComplexity = 16 // ceil(32/8)²
+	iterations := uint64(0)
+	if exponent.BitLen() > 0 {
+		iterations = uint64(exponent.BitLen() - 1)
+	}
+	if iterations == 0 {
+		iterations = 1
+	}
+	gas := multiplicationComplexity * iterations / 3
+	if gas < modexpMin {
+		return modexpMin
+	}
+	return gas
+}
+
+// Calldata is the size of the calldata of Verify.
+type Calldata struct {
+	Bytes int
+
+	// ZeroBytes is the number of zero bytes of the ABI encoding (offsets and
+	// lengths), the proof and the public inputs are assumed to have none: it is
+	// a lower bound, the public inputs often have many.
+	ZeroBytes int
+}
+
+// EstimateCalldata returns the size of the calldata of Verify(bytes,uint256[])
+// with a proof holding nbCommitments BSB22 commitments.
+func EstimateCalldata(nbCommitments, nbPublicInputs int) Calldata {
+
+	proofSize := calldata.ProofSize(nbCommitments)
+
+	// selector, offsets of the proof and of the public inputs, length and content
+	// of the proof, length and content of the public inputs. An offset or a
+	// length fits in 2 bytes, the rest of their word is zero.
+	const header = 4 + 2*32
+	bytes := header + 32 + proofSize + 32 + 32*nbPublicInputs
+	return Calldata{Bytes: bytes, ZeroBytes: 4 * 30}
+}
+
+// Gas returns the gas of the calldata in a transaction (EIP-2028): 4 per zero
+// byte, 16 per non zero byte.
+func (c Calldata) Gas() uint64 {
+	return uint64(4*c.ZeroBytes + 16*(c.Bytes-c.ZeroBytes))
+}
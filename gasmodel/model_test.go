@@ -0,0 +1,83 @@
+package gasmodel
+
+import (
+	"math"
+	"math/big"
+	"os/exec"
+	"testing"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/evmrun"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
+)
+
+// tolerance is the error of EstimateGas tolerated on the examples, in percent,
+// the default -model-tolerance of cmd/gasbench
+const tolerance = 5
+
+func TestEstimateGasExamples(t *testing.T) {
+
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip("solc is not in $PATH")
+	}
+
+	for i := range circuits.Examples {
+		example := &circuits.Examples[i]
+		t.Run(example.Name, func(t *testing.T) {
+
+			setup, err := example.Setup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, pi, err := setup.Prove(example.Assignment())
+			if err != nil {
+				t.Fatal(err)
+			}
+			publicInputs := make([]*big.Int, len(pi))
+			for i := range pi {
+				publicInputs[i] = pi[i].BigInt(new(big.Int))
+			}
+
+			sources, err := tmpl.GenerateVerifierSources(*setup.VK, tmpl.Options{Kind: tmpl.Contract, AllowTestSRS: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			contracts, err := solc.Compile(sources, solc.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifier, err := solc.Find(contracts, "PlonkVerifier")
+			if err != nil {
+				t.Fatal(err)
+			}
+			runner, err := evmrun.Deploy(verifier.Bin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proofBytes := calldata.MarshalProof(*proof)
+			res, err := runner.Verify("Verify", proofBytes, publicInputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res.Accepted {
+				t.Fatalf("the valid proof is rejected: %v", res.Err)
+			}
+
+			m := Measure{VK: setup.VK, NbPublicInputs: len(pi), Gas: res.ExecutionGas}
+			e := EstimateGas(setup.VK, len(pi))
+			if relative := DefaultCoefficients.Error(m); math.Abs(relative) > tolerance {
+				t.Errorf("estimate %d gas, measured %d: %.2f%% of error, %d%% tolerated", e.Gas, res.ExecutionGas, relative, tolerance)
+			}
+
+			input, err := calldata.EncodeVerifyCall("Verify", proofBytes, publicInputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.Calldata.Bytes != len(input) {
+				t.Errorf("estimate %d bytes of calldata, measured %d", e.Calldata.Bytes, len(input))
+			}
+		})
+	}
+}
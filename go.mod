@@ -2,6 +2,12 @@ module github.com/consensys/plonk-solidity
 
 go 1.19
 
+require (
+	github.com/consensys/gnark v0.7.2-0.20230524182320-52df4cfd203e
+	github.com/consensys/gnark-crypto v0.11.1-0.20230508024855-0cd4994b7f0b
+	github.com/ethereum/go-ethereum v1.11.6
+)
+
 require (
 	github.com/DataDog/zstd v1.5.2 // indirect
 	github.com/StackExchange/wmi v0.0.0-20180116203802-5d049714c4a6 // indirect
@@ -16,13 +22,10 @@ require (
 	github.com/cockroachdb/pebble v0.0.0-20230209160836-829675f94811 // indirect
 	github.com/cockroachdb/redact v1.1.3 // indirect
 	github.com/consensys/bavard v0.1.13 // indirect
-	github.com/consensys/gnark v0.7.2-0.20230524182320-52df4cfd203e // indirect
-	github.com/consensys/gnark-crypto v0.11.1-0.20230508024855-0cd4994b7f0b // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/deckarep/golang-set/v2 v2.1.0 // indirect
 	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.2.0 // indirect
 	github.com/edsrzf/mmap-go v1.0.0 // indirect
-	github.com/ethereum/go-ethereum v1.11.6 // indirect
 	github.com/fsnotify/fsnotify v1.6.0 // indirect
 	github.com/fxamacker/cbor/v2 v2.4.0 // indirect
 	github.com/gballet/go-libpcsclite v0.0.0-20190607065134-2772fd86a8ff // indirect
@@ -1,365 +0,0 @@
-// Code generated - DO NOT EDIT.
-// This file is a generated binding and any manual changes will be lost.
-
-package contract
-
-import (
-	"math/big"
-	"strings"
-
-	ethereum "github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/event"
-)
-
-// Reference imports to suppress errors if they are not otherwise used.
-var (
-	_ = big.NewInt
-	_ = strings.NewReader
-	_ = ethereum.NotFound
-	_ = bind.Bind
-	_ = common.Big1
-	_ = types.BloomLookup
-	_ = event.NewSubscription
-)
-
-// ContractABI is the input ABI used to generate the binding from.
-const ContractABI = "[{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"a\",\"type\":\"bool\"}],\"name\":\"PrintBool\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"test_verifier\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes\",\"name\":\"proof\",\"type\":\"bytes\"},{\"internalType\":\"uint256[]\",\"name\":\"public_inputs\",\"type\":\"uint256[]\"}],\"name\":\"test_verifier_go\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
-
-// ContractBin is the compiled bytecode used for deploying new contracts.
-var ContractBin = "0x608060405234801561001057600080fd5b50613e45806100206000396000f3fe608060405234801561001057600080fd5b50600436106100365760003560e01c80638cbba7b71461003b578063c383590a14610045575b600080fd5b610043610061565b005b61005f600480360381019061005a91906135b6565b610299565b005b6000600a67ffffffffffffffff81111561007e5761007d61338d565b5b6040519080825280602002602001820160405280156100ac5781602001602082028036833780820191505090505b509050600a816000815181106100c5576100c461362e565b5b602002602001018181525050600b816001815181106100e7576100e661362e565b5b602002602001018181525050600c816002815181106101095761010861362e565b5b602002602001018181525050600d8160038151811061012b5761012a61362e565b5b602002602001018181525050600e8160048151811061014d5761014c61362e565b5b602002602001018181525050600f8160058151811061016f5761016e61362e565b5b6020026020010181815250506010816006815181106101915761019061362e565b5b6020026020010181815250506011816007815181106101b3576101b261362e565b5b6020026020010181815250506012816008815181106101d5576101d461362e565b5b6020026020010181815250506013816009815181106101f7576101f661362e565b5b602002602001018181525050600061020d6102ec565b9050600061021b8284610943565b90507f3e1a1345e360785a18ee3f9d25ba93d69ea5c3b5772b44df827662204209612c8160405161024c9190613678565b60405180910390a180610294576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161028b906136f0565b60405180910390fd5b505050565b60006102a58383610943565b9050806102e7576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016102de906136f0565b60405180910390fd5b505050565b60606102f6613263565b7f1045d25fa2650c99fe03af4cda6c6b3037296dbeb57af8a9604160e4e0a8283a8160000181815250507f0dae8a5b6fd1240866576fb2424e539b95bf0c5bda67e02d2ecbaaaea28b416d8160200181815250507e8ff9c88408ae2e9dc34d6bda0d49849e0880bc82174f9be4ef25cfa936c2fb8160400181815250507f05e29b7eb6fbd0c12355d02783877f5038fbb3d2479c2611327cb1d42eaa23118160600181815250507f2f5105ed99bef98219fa1b1d9b867da4b3191119bd07fc9b293db1626829b3f68160800181815250507f2a7fd659cdb3cfdd43091ff7af0e3a099fe3730eb1e3b7fc15ff1dda7cfa18d18160a00181815250507f1b8a88a10ce417477206f476dd6f0f121ed68fb51462e9fced9508d902e03d2a8160c00181815250507f2e79570089ab9f75daf85e65b43f9711c50fb46fde95e439580533db1af561898160e00181815250507f2c150bc4c1ba3caa565c2b094930f4f43a81b5e928138e6898f7963f64bd5621816101000181815250507f1263b363d57c001700a07424fdd32f093f4ed73ee4116f8cc8aaa895a3088a43816101200181815250507f222b5732cc784373e52ad7ddb06ff513d2acfd7bafb5238c54042894a0912458816101400181815250507f2b868d2688a3c31855222e85b30be7521a8f8776d67a403934e2fd0a42d709bb816101600181815250507f2ea3c1aa6f8d5ad820413ada3f9a5cc4d1913069b0ff10d356c5700804d6fd1c816101800181815250507f2a5c129fd2c5d9a85db2fd16c47fbeb0d5a835fe8f83314c5434b3638022b208816101a00181815250507f04afbdbe3d172a2d30a4799ec660e246dad1e5f381bc24ebe3a70a4e58d84266816101c00181815250507eed3fa0ce0bcb07f84d829615ec9c8f0ce8dc1695a5340521b6146563c3d2cf816101e00181815250507f0db532142ed00293e3f9a68df81cd7052404ba09361ca71d69ac79eceb4bd5f0816102000181815250507f1838627b74b5a2307ff8616d2fa409fce0c7a2b1f87be0584cc9a03729bf7b3d816102200181815250507f2a45758f86cda6c018bd4d4c8f8a67245ccfe571f816ed7c1e5d0778b06129b7816102400181815250507f0a3864555ad589cd921d0464c32dcabd85bc125d43d2e88f2a789b6cd4d4c325816102600181815250507f049a6914f56ae45f3099991852da1592fff0503ec6a0695fd7d8d090a6756070816102800181815250507f053cfc858fdebfbe99e3eab1617e8c19b6ecaf2e686186a3651f730eab5c561f816102a00181815250507f07b7e37415227af0600121aa1662ac9b901aabc8126742d678cf52331ceb00b0816102c00181815250507f137b484051284f89ea560c4377c716b35be40283c5fc0305ab62dda76b884c5f816102e00181815250507f223dcccf788fbfd7e169a0fef3413753a3ec2c9b0973b7646de13bc896e5d70c816103000181815250507f1ed6e68bcd6120d2cc4fab4239dc128266e55434656cfcbe29289ccd3a6b4272816103200181815250506000816103400181815250507f2a5edd0cfa4988971c9020755aa62e22850140f368750bdb3b6b823754477262816103600181815250507f16badb6b61fce667302fc374349fa99a8fd437dfdfd672ae3eba0d73e08027e8816103800181815250506060816000015182602001518360400151846060015185608001518660a001518760c001518860e001518961010001518a61012001518b61014001518c61016001516040516020016108099c9b9a99989796959493929190613731565b604051602081830303815290604052905080826101800151836101a00151846101c001516040516020016108409493929190613878565b604051602081830303815290604052905080826101e00151836102000151846102200151856102400151866102600151876102800151886102a001516040516020016108939897969594939291906138c2565b604051602081830303815290604052905080826102c00151836102e001518461030001518561032001516040516020016108d1959493929190613950565b6040516020818303038152906040529050808261034001516040516020016108fa9291906139ab565b60405160208183030381529060405290508082610360015183610380015160405160200161092a939291906139d3565b6040516020818303038152906040529050809250505090565b60008060008060006109558787611f5b565b80945081955082965083975050505050600061097288888461246c565b905060008060009050604051856000820152876040820152846060820152866020820152836101c08201526109a56109f0565b6109ae8b611ada565b6109b78b611a65565b6109c08b61165f565b6109c98b611141565b6109d28b610ecd565b6109db8b610b9f565b61024081015191506102608101519250611f4a565b60405161028060405101610a0a8160406060850151611eeb565b7f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160017f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000010382089050806101e08401527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160017f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000103606085015108610ad38360027f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000010383611eeb565b90507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000017f2fa2bd3915acd9a9116f049fa77b52fbb39318a757d28acefed26dbda0400001820990507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001828209915060008401517f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000181840992507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000181840992508260c08601525050505050565b60405161028081017f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016020832006816040810192506102e085015181526103008501516020820152610bf78383610320880184611e3b565b6101808401610c0c8484610240890184611e3b565b6101608501610c2084610280890183611e99565b8460408101955060018152600260208201528151604082015260408160608360076107d05a03fa506020810180517f30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47038152610c7e87838687611dba565b86604088019750610c998860608b01516102e08d0184611e00565b7f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000017f1418144d5b080fcac24cdb7649bdadf246a6cb2426e324bedb94fb05118f023a60608b0151097f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000018189099750610d1789896103208e0185611e3b565b610d2389838889611dba565b6020870180517f30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd4703815286518a52602087015160208b01527f198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c260408b01527f1800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed60608b01527f090689d0585ff075ec9e99ad690c3395bc4b313370b38ef355acdadcd122975b60808b01527f12c85ea5db8c6deb4aab71808dcb408fe3d1e7690c43d37b4ce6cc0166fa7daa60a08b0152875160c08b0152602088015160e08b01527f1298d7f25179db22de0a4a2b5a6b47396ce21cc9480d69a9ee4f01b7b69841306101008b01527f2a9eeda047f7e9d1fe7fe35cef8c473b181a027ffafdc51591fdf74ef258a3e96101208b01527f1ae9f68941599171e8f6bd9de79cdb1b12be7b9fdf8b4ae638b5b06bbc58dd756101408b01527f16ef7bea6422a0ac77df5ac62a13936a592e54fe899235ca4b1222924cfe904e6101608b0152602060006101808c60086107d05a03fa6102408c015181166102408d015250505050505050505050505050565b6040516102806040510161022082015180604060010261020001808401604085015161018087015260608501516101a08701526102a0870151610160870152610f1f8184608088016101808a01611e3b565b610f32836102c089016101608901611e99565b826102608701527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000018484099250610f72818460c088016101808a01611e3b565b610f85836101a089016101608901611e99565b7f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000018484099250610fc18286018461010088016101808a01611e3b565b610fd4836101c089016101608901611e99565b7f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000184840992506110108286018461014088016101808a01611e3b565b611023836101e089016101608901611e99565b7f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001848409925061105f8286018461018088016101808a01611e3b565b6110728361020089016101608901611e99565b7f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000184840992506110ae828601846101c088016101808a01611e3b565b6110c18361022089016101608901611e99565b6103608701610200860160005b6001811015611135577f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001878709955061110f85890187846101808d01611e3b565b61111e86846101608c01611e99565b6020830192506040820191506001810190506110ce565b50505050505050505050565b604051610280604051016467616d6d6181526060820151602082015260e082015160408201526101008201516060820152610120820151608082015261014082015160a0820152602083015160c0820152604083015160e08201526060830151610100820152608083015161012082015260a083015161014082015260c08301516101608201527f14d49730c5707665e8ddcfed2a9f7dc9e329ea1b48b622d6669d2f781d3659eb6101808201527f295707158aa7d9b1a8ca9d067ae3600fd6d12a2dbe12b1f4e31b5f9dda4714416101a08201527f2dedfa8013e411dd064f7fdf1cb1c426bf8d74de10ed5b86a571a510a7b07a856101c08201527f2cb21d36723a96c119e4158ebe87188ac847514d5b8d2be7a35b54092ecf9a8d6101e08201526102006000818301526000602082018301526040810190506102a0840151818301526102c0840151602082018301526101a0840151604082018301526101c0840151606082018301526101e08401516080820183015261020084015160a0820183015261022084015160c0820183015260e081018201610360850160005b600181101561130657815183526020820191506020830192506001810190506112e2565b50601b6003600102601601602081026005019050602061022088018284890160026107d05a03fa507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001610220880151066102208801525050505050505050565b604051610280604051017f2dee8a73d62df6a0cb9433efba2acf52907e65a4d026f305209db5b9c4fc7a9081527f1508def56ea44749ec1221b939233da16eef509e153a195de94232cb5efe4b6c60208201526113d1604082016101a0850151836101208601611e00565b7f2574c624cf712b3f140e180c25d22123a30e24a672ab79e682acd3b1b6cb608a81527f1070218a1253eadc72c6d0f2d4def208e387698d6793cf0c78a21a85200d5ae46020820152611432604082016101c0850151836101208601611e3b565b7f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016101c08401516101a0850151097f02c824eddf5edea72d58cd612d5f48a10842e8dec3d573918483db6f7c6481c382527f2b6f8a2f7a65cf1280fb2f620ee22bbdeaa45cb9f9a966989805824bcabbe0ee60208301526114bc6040830182846101208701611e3b565b7f1a1c8cb639502e7931bd2efca587445ca576784be85f71dd41db5b0c93ec586c82527f06dbbd2446bb7107f295d445271400862d592fbc986667c3ed3b170ab4895ba0602083015261151d604083016101e0860151846101208701611e3b565b7f2c268581d9f64fb27d62a3f450c4b9e5e2d8b3d8b3e88822a479cd667b441c0882527f0d3f81117c03148665268a643d6ab66f4b10ad4b292d5b2f9b55e350ffddc9b0602083015261157d604083018361012086016101208701611dba565b6103608401602060010261036001850160005b60018110156115d25781518552602082015160208601526115bb604086018451876101208a01611e3b565b602083019250604082019150600181019050611590565b507f1198edc947ae6fcebd7e182d24781b85c2fa5f2fd4af7861a18e17516b2ed5bf84527f13a93aa4296d7d311eb6bb43e42d740c8af761b16f820c83c0cce5752202af92602085015261162f6040850188866101208901611e3b565b610240860151845261026086015160208501526116556040850189866101208901611e3b565b5050505050505050565b60405160208101516040820151606083015160008401517f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000184610280880151097f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000161020088015186097f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016101a0890151820890507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000185820890507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000161022089015187097f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016101c08a0151820890507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000186820890507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000018284097f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000182820990507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000185820990507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001600580097f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001878a097f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016101a08d0151820895507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000189870895507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016005820994507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016101c08d0151860894507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000189860894507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000182820993507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016101e08d0151850893507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000189850893507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000018587097f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000018582099050807f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000010390507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000188820990507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160c08d015182089050611a5681858f611366565b50505050505050505050505050565b604051600260400161028060405101611a8381836060860151611eeb565b611a968282610160880160e08801611e00565b611aac82610120870160e0870160e08801611dba565b611abe828260e0870160e08801611e00565b611ad38260e0870160e0870160e08801611dba565b5050505050565b604051610280604051017f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160208301516102008501510981527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001604083015182510881527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016101a08401518251088152602081017f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160208401516102208601510981527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001604084015182510881527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016101c08501518251088152604082017f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160408501516101e08701510881527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001825184510983527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001815184510983527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001600085015184510983527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016102808601518451098352606083017f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016101c08601516102c08801510881527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001845182510881527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160c08601517f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000010382510881527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016101e08601516102a08801510983528251815114610240860152505050505050565b6040518251855260208301516020860152835160408601526020840151606086015260408260808760066107d05a03fa6102408201518116610240830152505050505050565b604051825185526020830151602086015283604086015260408260608760076107d05a03fa6102408201518116610240830152505050505050565b604051825185526020830151602086015283604086015260408560608760076107d05a03fa825160408701526020830151606087015260408360808860066107d05a03fa811690506102408201518116610240830152505050505050565b7f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001838351097f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000181835108825250505050565b600060208452602080850152602060408501528160608501528260808501527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160a085015260208460c08660056107d05a03fa50835190509392505050565b508097505050505050505092915050565b600080600080600080600080604051611f748a8c61203b565b80519450611f82858c612385565b80519350611f90848c6123ac565b80519250611f9e838c6123ea565b805191507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001850694507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001840693507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001830692507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000182069150612452565b6040516467616d6d6181527f14d49730c5707665e8ddcfed2a9f7dc9e329ea1b48b622d6669d2f781d3659eb60208201527f295707158aa7d9b1a8ca9d067ae3600fd6d12a2dbe12b1f4e31b5f9dda47144160408201527f2dedfa8013e411dd064f7fdf1cb1c426bf8d74de10ed5b86a571a510a7b07a8560608201527f2cb21d36723a96c119e4158ebe87188ac847514d5b8d2be7a35b54092ecf9a8d60808201527f1198edc947ae6fcebd7e182d24781b85c2fa5f2fd4af7861a18e17516b2ed5bf60a08201527f13a93aa4296d7d311eb6bb43e42d740c8af761b16f820c83c0cce5752202af9260c08201527f2dee8a73d62df6a0cb9433efba2acf52907e65a4d026f305209db5b9c4fc7a9060e08201527f1508def56ea44749ec1221b939233da16eef509e153a195de94232cb5efe4b6c6101008201527f2574c624cf712b3f140e180c25d22123a30e24a672ab79e682acd3b1b6cb608a6101208201527f1070218a1253eadc72c6d0f2d4def208e387698d6793cf0c78a21a85200d5ae46101408201527f02c824eddf5edea72d58cd612d5f48a10842e8dec3d573918483db6f7c6481c36101608201527f2b6f8a2f7a65cf1280fb2f620ee22bbdeaa45cb9f9a966989805824bcabbe0ee6101808201527f1a1c8cb639502e7931bd2efca587445ca576784be85f71dd41db5b0c93ec586c6101a08201527f06dbbd2446bb7107f295d445271400862d592fbc986667c3ed3b170ab4895ba06101c08201527f2c268581d9f64fb27d62a3f450c4b9e5e2d8b3d8b3e88822a479cd667b441c086101e08201527f0d3f81117c03148665268a643d6ab66f4b10ad4b292d5b2f9b55e350ffddc9b061020082015260208301610220820160005b85518110156122df57825182526020830192506020820191506001810190506122bb565b50610360840160206001028101905060005b600181101561231f5781518352602082015160208401526040830192506040820191506001810190506122f1565b506020850151825260408501516020830152606085015160408301526080850151606083015260a0850151608083015260c085015160a083015260208651026102c50160406001028101905060208582601b880160026107d05a03fa5050505050505050565b604051636265746181528260208201526020816024601c840160026107d05a03fa50505050565b60405164616c7068618152826020820152610240820151604082015261026082015160608201526020816065601b840160026107d05a03fa50505050565b604051637a657461815282602082015260e082015160408201526101008201516060820152610120820151608082015261014082015160a082015261016082015160c082015261018082015160e082015260208160e4601c840160026107d05a03fa50505050565b508383838397509750975097505050505092959194509250565b60008060006124808486516020880161248b565b604051519050612898565b60405161249981848661251a565b60008060005b8581101561250c577f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001855185510991507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001828408925060208401935060208501945060018101905061249f565b508160405152505050505050565b7f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160017f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000010361256b85604085612839565b087f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000017f2fa2bd3915acd9a9116f049fa77b52fbb39318a757d28acefed26dbda04000018209905060018460005b8581101561265f577f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001837f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000103860882527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000017f1418144d5b080fcac24cdb7649bdadf246a6cb2426e324bedb94fb05118f023a840992506020820191506001810190506125b8565b5061266b818688612727565b8590506001915060005b8581101561271e577f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001837f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001868551090982526020820191507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000017f1418144d5b080fcac24cdb7649bdadf246a6cb2426e324bedb94fb05118f023a84099250600181019050612675565b50505050505050565b600183526000805b8381101561277d5781850151828401517f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000181830990506020840193508084880152505060018101905061272f565b50602081038201915080840193506127bd6020850160027f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001038651612839565b60005b848110156128315760208603955083517f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001875184098086527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001828509935060208603955050506001810190506127c0565b505050505050565b600060208452602080850152602060408501528160608501528260808501527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160a085015260208460c08660056107d05a03fa50835190509392505050565b6128a6604051604086612839565b91507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160017f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000103830891506000600167ffffffffffffffff81111561290e5761290d61338d565b5b60405190808252806020026020018201604052801561293c5781602001602082028036833780820191505090505b50905061294881612ac2565b6060600160026129589190613a3b565b67ffffffffffffffff8111156129715761297061338d565b5b60405190808252806020026020018201604052801561299f5781602001602082028036833780820191505090505b5090506129ac8189612ad4565b60005b6001811015612ab3576000612a1c838360026129cb9190613a3b565b815181106129dc576129db61362e565b5b60200260200101518460018560026129f49190613a3b565b6129fe9190613a7d565b81518110612a0f57612a0e61362e565b5b6020026020010151612b27565b90506000612a50898b51878681518110612a3957612a3861362e565b5b6020026020010151612a4b9190613a7d565b612ca9565b90507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000181830990507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001818708955050508080612aab90613ab1565b9150506129af565b50829450505050509392505050565b60208101600a81526020810190505050565b60208201610360820160206001028101905060005b6002600102811015612b20578151835260208301925060208201915081518352602083019250602082019150600181019050612ae9565b5050505050565b600080612b348484612ea9565b905060005b6020811015612b9857806008612b4f9190613a3b565b8282602f612b5d9190613af9565b60308110612b6e57612b6d61362e565b5b602002015160ff16901b83612b839190613a7d565b92508080612b9090613ab1565b915050612b39565b507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000182612bc59190613b5c565b9150600080600090505b6010811015612c2e57806008612be59190613a3b565b8382600f612bf39190613af9565b60308110612c0457612c0361362e565b5b602002015160ff16901b82612c199190613a7d565b91508080612c2690613ab1565b915050612bcf565b5060007f0e0a77c19a07df2f666ea36f7879462e36fc76959f60cd29ac96341c4ffffffb90507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000181830991507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001828508935050505092915050565b600080612d15565b600060405160208152602080820152602060408201528260608201528360808201527f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160a08201526020600060c08360056107d05a03fa5060005191505092915050565b612d3f837f1418144d5b080fcac24cdb7649bdadf246a6cb2426e324bedb94fb05118f023a612cb1565b7f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001817f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000010386089350612d92604086612cb1565b94507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000160017f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000103860894507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000017f2fa2bd3915acd9a9116f049fa77b52fbb39318a757d28acefed26dbda040000182099050612e5060027f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000010385612cb1565b93507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000184820990507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000018582099150508091505092915050565b612eb161333b565b60006040518060400160405280600b81526020017f42534232322d506c6f6e6b00000000000000000000000000000000000000000081525090506060600080603090506000600b905060005b6040811015612f3d578484604051602001612f19929190613bd0565b60405160208183030381529060405294508080612f3590613ab1565b915050612efd565b508388888585878a87604051602001612f5d989796959493929190613c3f565b60405160208183030381529060405293506000600285604051612f809190613cc9565b602060405180830381855afa158015612f9d573d6000803e3d6000fd5b5050506040513d601f19601f82011682018060405250810190612fc09190613d16565b90508060018784604051602001612fda9493929190613d64565b60405160208183030381529060405294506000600286604051612ffd9190613cc9565b602060405180830381855afa15801561301a573d6000803e3d6000fd5b5050506040513d601f19601f8201168201806040525081019061303d9190613d16565b905060005b602081101561309c5781816020811061305e5761305d61362e565b5b1a60f81b60f81c8982603081106130785761307761362e565b5b602002019060ff16908160ff1681525050808061309490613ab1565b915050613042565b50806000602081106130b1576130b061362e565b5b1a60f81b60f81c826000602081106130cc576130cb61362e565b5b1a60f81b60f81c186040516020016130e49190613dae565b60405160208183030381529060405295506000600190505b602081101561317057868282602081106131195761311861362e565b5b1a60f81b60f81c8483602081106131335761313261362e565b5b1a60f81b60f81c1860405160200161314c929190613bd0565b6040516020818303038152906040529650808061316890613ab1565b9150506130fc565b5085600288856040516020016131899493929190613dc9565b60405160208183030381529060405295506002866040516131aa9190613cc9565b602060405180830381855afa1580156131c7573d6000803e3d6000fd5b5050506040513d601f19601f820116820180604052508101906131ea9190613d16565b905060005b60108110156132555781816020811061320b5761320a61362e565b5b1a60f81b60f81c896020836132209190613a7d565b603081106132315761323061362e565b5b602002019060ff16908160ff1681525050808061324d90613ab1565b9150506131ef565b505050505050505092915050565b604051806103a0016040528060008152602001600081526020016000815260200160008152602001600081526020016000815260200160008152602001600081526020016000815260200160008152602001600081526020016000815260200160008152602001600081526020016000815260200160008152602001600081526020016000815260200160008152602001600081526020016000815260200160008152602001600081526020016000815260200160008152602001600081526020016000815260200160008152602001600081525090565b604051806106000160405280603090602082028036833780820191505090505090565b6000604051905090565b600080fd5b600080fd5b600080fd5b600080fd5b6000601f19601f8301169050919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052604160045260246000fd5b6133c58261337c565b810181811067ffffffffffffffff821117156133e4576133e361338d565b5b80604052505050565b60006133f761335e565b905061340382826133bc565b919050565b600067ffffffffffffffff8211156134235761342261338d565b5b61342c8261337c565b9050602081019050919050565b82818337600083830152505050565b600061345b61345684613408565b6133ed565b90508281526020810184848401111561347757613476613377565b5b613482848285613439565b509392505050565b600082601f83011261349f5761349e613372565b5b81356134af848260208601613448565b91505092915050565b600067ffffffffffffffff8211156134d3576134d261338d565b5b602082029050602081019050919050565b600080fd5b6000819050919050565b6134fc816134e9565b811461350757600080fd5b50565b600081359050613519816134f3565b92915050565b600061353261352d846134b8565b6133ed565b90508083825260208201905060208402830185811115613555576135546134e4565b5b835b8181101561357e578061356a888261350a565b845260208401935050602081019050613557565b5050509392505050565b600082601f83011261359d5761359c613372565b5b81356135ad84826020860161351f565b91505092915050565b600080604083850312156135cd576135cc613368565b5b600083013567ffffffffffffffff8111156135eb576135ea61336d565b5b6135f78582860161348a565b925050602083013567ffffffffffffffff8111156136185761361761336d565b5b61362485828601613588565b9150509250929050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052603260045260246000fd5b60008115159050919050565b6136728161365d565b82525050565b600060208201905061368d6000830184613669565b92915050565b600082825260208201905092915050565b7f766572696669636174696f6e206661696c656421000000000000000000000000600082015250565b60006136da601483613693565b91506136e5826136a4565b602082019050919050565b60006020820190508181036000830152613709816136cd565b9050919050565b6000819050919050565b61372b613726826134e9565b613710565b82525050565b600061373d828f61371a565b60208201915061374d828e61371a565b60208201915061375d828d61371a565b60208201915061376d828c61371a565b60208201915061377d828b61371a565b60208201915061378d828a61371a565b60208201915061379d828961371a565b6020820191506137ad828861371a565b6020820191506137bd828761371a565b6020820191506137cd828661371a565b6020820191506137dd828561371a565b6020820191506137ed828461371a565b6020820191508190509d9c50505050505050505050505050565b600081519050919050565b600081905092915050565b60005b8381101561383b578082015181840152602081019050613820565b60008484015250505050565b600061385282613807565b61385c8185613812565b935061386c81856020860161381d565b80840191505092915050565b60006138848287613847565b9150613890828661371a565b6020820191506138a0828561371a565b6020820191506138b0828461371a565b60208201915081905095945050505050565b60006138ce828b613847565b91506138da828a61371a565b6020820191506138ea828961371a565b6020820191506138fa828861371a565b60208201915061390a828761371a565b60208201915061391a828661371a565b60208201915061392a828561371a565b60208201915061393a828461371a565b6020820191508190509998505050505050505050565b600061395c8288613847565b9150613968828761371a565b602082019150613978828661371a565b602082019150613988828561371a565b602082019150613998828461371a565b6020820191508190509695505050505050565b60006139b78285613847565b91506139c3828461371a565b6020820191508190509392505050565b60006139df8286613847565b91506139eb828561371a565b6020820191506139fb828461371a565b602082019150819050949350505050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052601160045260246000fd5b6000613a46826134e9565b9150613a51836134e9565b9250828202613a5f816134e9565b91508282048414831517613a7657613a75613a0c565b5b5092915050565b6000613a88826134e9565b9150613a93836134e9565b9250828201905080821115613aab57613aaa613a0c565b5b92915050565b6000613abc826134e9565b91507fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff8203613aee57613aed613a0c565b5b600182019050919050565b6000613b04826134e9565b9150613b0f836134e9565b9250828203905081811115613b2757613b26613a0c565b5b92915050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052601260045260246000fd5b6000613b67826134e9565b9150613b72836134e9565b925082613b8257613b81613b2d565b5b828206905092915050565b600060ff82169050919050565b60008160f81b9050919050565b6000613bb282613b9a565b9050919050565b613bca613bc582613b8d565b613ba7565b82525050565b6000613bdc8285613847565b9150613be88284613bb9565b6001820191508190509392505050565b600081519050919050565b600081905092915050565b6000613c1982613bf8565b613c238185613c03565b9350613c3381856020860161381d565b80840191505092915050565b6000613c4b828b613847565b9150613c57828a61371a565b602082019150613c67828961371a565b602082019150613c778288613bb9565b600182019150613c878287613bb9565b600182019150613c978286613bb9565b600182019150613ca78285613c0e565b9150613cb38284613bb9565b6001820191508190509998505050505050505050565b6000613cd58284613847565b915081905092915050565b6000819050919050565b613cf381613ce0565b8114613cfe57600080fd5b50565b600081519050613d1081613cea565b92915050565b600060208284031215613d2c57613d2b613368565b5b6000613d3a84828501613d01565b91505092915050565b6000819050919050565b613d5e613d5982613ce0565b613d43565b82525050565b6000613d708287613d4d565b602082019150613d808286613bb9565b600182019150613d908285613c0e565b9150613d9c8284613bb9565b60018201915081905095945050505050565b6000613dba8284613bb9565b60018201915081905092915050565b6000613dd58287613847565b9150613de18286613bb9565b600182019150613df18285613c0e565b9150613dfd8284613bb9565b6001820191508190509594505050505056fea2646970667358221220d15fb7eb5796e9a3841e44f576b52dbf41cc9620251f84ac3d2e8795f5fb13fe64736f6c63430008130033"
-
-// DeployContract deploys a new Ethereum contract, binding an instance of Contract to it.
-func DeployContract(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *Contract, error) {
-	parsed, err := abi.JSON(strings.NewReader(ContractABI))
-	if err != nil {
-		return common.Address{}, nil, nil, err
-	}
-
-	address, tx, contract, err := bind.DeployContract(auth, parsed, common.FromHex(ContractBin), backend)
-	if err != nil {
-		return common.Address{}, nil, nil, err
-	}
-	return address, tx, &Contract{ContractCaller: ContractCaller{contract: contract}, ContractTransactor: ContractTransactor{contract: contract}, ContractFilterer: ContractFilterer{contract: contract}}, nil
-}
-
-// Contract is an auto generated Go binding around an Ethereum contract.
-type Contract struct {
-	ContractCaller     // Read-only binding to the contract
-	ContractTransactor // Write-only binding to the contract
-	ContractFilterer   // Log filterer for contract events
-}
-
-// ContractCaller is an auto generated read-only Go binding around an Ethereum contract.
-type ContractCaller struct {
-	contract *bind.BoundContract // Generic contract wrapper for the low level calls
-}
-
-// ContractTransactor is an auto generated write-only Go binding around an Ethereum contract.
-type ContractTransactor struct {
-	contract *bind.BoundContract // Generic contract wrapper for the low level calls
-}
-
-// ContractFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
-type ContractFilterer struct {
-	contract *bind.BoundContract // Generic contract wrapper for the low level calls
-}
-
-// ContractSession is an auto generated Go binding around an Ethereum contract,
-// with pre-set call and transact options.
-type ContractSession struct {
-	Contract     *Contract         // Generic contract binding to set the session for
-	CallOpts     bind.CallOpts     // Call options to use throughout this session
-	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
-}
-
-// ContractCallerSession is an auto generated read-only Go binding around an Ethereum contract,
-// with pre-set call options.
-type ContractCallerSession struct {
-	Contract *ContractCaller // Generic contract caller binding to set the session for
-	CallOpts bind.CallOpts   // Call options to use throughout this session
-}
-
-// ContractTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
-// with pre-set transact options.
-type ContractTransactorSession struct {
-	Contract     *ContractTransactor // Generic contract transactor binding to set the session for
-	TransactOpts bind.TransactOpts   // Transaction auth options to use throughout this session
-}
-
-// ContractRaw is an auto generated low-level Go binding around an Ethereum contract.
-type ContractRaw struct {
-	Contract *Contract // Generic contract binding to access the raw methods on
-}
-
-// ContractCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
-type ContractCallerRaw struct {
-	Contract *ContractCaller // Generic read-only contract binding to access the raw methods on
-}
-
-// ContractTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
-type ContractTransactorRaw struct {
-	Contract *ContractTransactor // Generic write-only contract binding to access the raw methods on
-}
-
-// NewContract creates a new instance of Contract, bound to a specific deployed contract.
-func NewContract(address common.Address, backend bind.ContractBackend) (*Contract, error) {
-	contract, err := bindContract(address, backend, backend, backend)
-	if err != nil {
-		return nil, err
-	}
-	return &Contract{ContractCaller: ContractCaller{contract: contract}, ContractTransactor: ContractTransactor{contract: contract}, ContractFilterer: ContractFilterer{contract: contract}}, nil
-}
-
-// NewContractCaller creates a new read-only instance of Contract, bound to a specific deployed contract.
-func NewContractCaller(address common.Address, caller bind.ContractCaller) (*ContractCaller, error) {
-	contract, err := bindContract(address, caller, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	return &ContractCaller{contract: contract}, nil
-}
-
-// NewContractTransactor creates a new write-only instance of Contract, bound to a specific deployed contract.
-func NewContractTransactor(address common.Address, transactor bind.ContractTransactor) (*ContractTransactor, error) {
-	contract, err := bindContract(address, nil, transactor, nil)
-	if err != nil {
-		return nil, err
-	}
-	return &ContractTransactor{contract: contract}, nil
-}
-
-// NewContractFilterer creates a new log filterer instance of Contract, bound to a specific deployed contract.
-func NewContractFilterer(address common.Address, filterer bind.ContractFilterer) (*ContractFilterer, error) {
-	contract, err := bindContract(address, nil, nil, filterer)
-	if err != nil {
-		return nil, err
-	}
-	return &ContractFilterer{contract: contract}, nil
-}
-
-// bindContract binds a generic wrapper to an already deployed contract.
-func bindContract(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
-	parsed, err := abi.JSON(strings.NewReader(ContractABI))
-	if err != nil {
-		return nil, err
-	}
-	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
-}
-
-// Call invokes the (constant) contract method with params as input values and
-// sets the output to result. The result type might be a single field for simple
-// returns, a slice of interfaces for anonymous returns and a struct for named
-// returns.
-func (_Contract *ContractRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
-	return _Contract.Contract.ContractCaller.contract.Call(opts, result, method, params...)
-}
-
-// Transfer initiates a plain transaction to move funds to the contract, calling
-// its default method if one is available.
-func (_Contract *ContractRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
-	return _Contract.Contract.ContractTransactor.contract.Transfer(opts)
-}
-
-// Transact invokes the (paid) contract method with params as input values.
-func (_Contract *ContractRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
-	return _Contract.Contract.ContractTransactor.contract.Transact(opts, method, params...)
-}
-
-// Call invokes the (constant) contract method with params as input values and
-// sets the output to result. The result type might be a single field for simple
-// returns, a slice of interfaces for anonymous returns and a struct for named
-// returns.
-func (_Contract *ContractCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
-	return _Contract.Contract.contract.Call(opts, result, method, params...)
-}
-
-// Transfer initiates a plain transaction to move funds to the contract, calling
-// its default method if one is available.
-func (_Contract *ContractTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
-	return _Contract.Contract.contract.Transfer(opts)
-}
-
-// Transact invokes the (paid) contract method with params as input values.
-func (_Contract *ContractTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
-	return _Contract.Contract.contract.Transact(opts, method, params...)
-}
-
-// TestVerifier is a paid mutator transaction binding the contract method 0x8cbba7b7.
-//
-// Solidity: function test_verifier() returns()
-func (_Contract *ContractTransactor) TestVerifier(opts *bind.TransactOpts) (*types.Transaction, error) {
-	return _Contract.contract.Transact(opts, "test_verifier")
-}
-
-// TestVerifier is a paid mutator transaction binding the contract method 0x8cbba7b7.
-//
-// Solidity: function test_verifier() returns()
-func (_Contract *ContractSession) TestVerifier() (*types.Transaction, error) {
-	return _Contract.Contract.TestVerifier(&_Contract.TransactOpts)
-}
-
-// TestVerifier is a paid mutator transaction binding the contract method 0x8cbba7b7.
-//
-// Solidity: function test_verifier() returns()
-func (_Contract *ContractTransactorSession) TestVerifier() (*types.Transaction, error) {
-	return _Contract.Contract.TestVerifier(&_Contract.TransactOpts)
-}
-
-// TestVerifierGo is a paid mutator transaction binding the contract method 0xc383590a.
-//
-// Solidity: function test_verifier_go(bytes proof, uint256[] public_inputs) returns()
-func (_Contract *ContractTransactor) TestVerifierGo(opts *bind.TransactOpts, proof []byte, public_inputs []*big.Int) (*types.Transaction, error) {
-	return _Contract.contract.Transact(opts, "test_verifier_go", proof, public_inputs)
-}
-
-// TestVerifierGo is a paid mutator transaction binding the contract method 0xc383590a.
-//
-// Solidity: function test_verifier_go(bytes proof, uint256[] public_inputs) returns()
-func (_Contract *ContractSession) TestVerifierGo(proof []byte, public_inputs []*big.Int) (*types.Transaction, error) {
-	return _Contract.Contract.TestVerifierGo(&_Contract.TransactOpts, proof, public_inputs)
-}
-
-// TestVerifierGo is a paid mutator transaction binding the contract method 0xc383590a.
-//
-// Solidity: function test_verifier_go(bytes proof, uint256[] public_inputs) returns()
-func (_Contract *ContractTransactorSession) TestVerifierGo(proof []byte, public_inputs []*big.Int) (*types.Transaction, error) {
-	return _Contract.Contract.TestVerifierGo(&_Contract.TransactOpts, proof, public_inputs)
-}
-
-// ContractPrintBoolIterator is returned from FilterPrintBool and is used to iterate over the raw logs and unpacked data for PrintBool events raised by the Contract contract.
-type ContractPrintBoolIterator struct {
-	Event *ContractPrintBool // Event containing the contract specifics and raw log
-
-	contract *bind.BoundContract // Generic contract to use for unpacking event data
-	event    string              // Event name to use for unpacking event data
-
-	logs chan types.Log        // Log channel receiving the found contract events
-	sub  ethereum.Subscription // Subscription for errors, completion and termination
-	done bool                  // Whether the subscription completed delivering logs
-	fail error                 // Occurred error to stop iteration
-}
-
-// Next advances the iterator to the subsequent event, returning whether there
-// are any more events found. In case of a retrieval or parsing error, false is
-// returned and Error() can be queried for the exact failure.
-func (it *ContractPrintBoolIterator) Next() bool {
-	// If the iterator failed, stop iterating
-	if it.fail != nil {
-		return false
-	}
-	// If the iterator completed, deliver directly whatever's available
-	if it.done {
-		select {
-		case log := <-it.logs:
-			it.Event = new(ContractPrintBool)
-			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
-				it.fail = err
-				return false
-			}
-			it.Event.Raw = log
-			return true
-
-		default:
-			return false
-		}
-	}
-	// Iterator still in progress, wait for either a data or an error event
-	select {
-	case log := <-it.logs:
-		it.Event = new(ContractPrintBool)
-		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
-			it.fail = err
-			return false
-		}
-		it.Event.Raw = log
-		return true
-
-	case err := <-it.sub.Err():
-		it.done = true
-		it.fail = err
-		return it.Next()
-	}
-}
-
-// Error returns any retrieval or parsing error occurred during filtering.
-func (it *ContractPrintBoolIterator) Error() error {
-	return it.fail
-}
-
-// Close terminates the iteration process, releasing any pending underlying
-// resources.
-func (it *ContractPrintBoolIterator) Close() error {
-	it.sub.Unsubscribe()
-	return nil
-}
-
-// ContractPrintBool represents a PrintBool event raised by the Contract contract.
-type ContractPrintBool struct {
-	A   bool
-	Raw types.Log // Blockchain specific contextual infos
-}
-
-// FilterPrintBool is a free log retrieval operation binding the contract event 0x3e1a1345e360785a18ee3f9d25ba93d69ea5c3b5772b44df827662204209612c.
-//
-// Solidity: event PrintBool(bool a)
-func (_Contract *ContractFilterer) FilterPrintBool(opts *bind.FilterOpts) (*ContractPrintBoolIterator, error) {
-
-	logs, sub, err := _Contract.contract.FilterLogs(opts, "PrintBool")
-	if err != nil {
-		return nil, err
-	}
-	return &ContractPrintBoolIterator{contract: _Contract.contract, event: "PrintBool", logs: logs, sub: sub}, nil
-}
-
-// WatchPrintBool is a free log subscription operation binding the contract event 0x3e1a1345e360785a18ee3f9d25ba93d69ea5c3b5772b44df827662204209612c.
-//
-// Solidity: event PrintBool(bool a)
-func (_Contract *ContractFilterer) WatchPrintBool(opts *bind.WatchOpts, sink chan<- *ContractPrintBool) (event.Subscription, error) {
-
-	logs, sub, err := _Contract.contract.WatchLogs(opts, "PrintBool")
-	if err != nil {
-		return nil, err
-	}
-	return event.NewSubscription(func(quit <-chan struct{}) error {
-		defer sub.Unsubscribe()
-		for {
-			select {
-			case log := <-logs:
-				// New log arrived, parse the event and forward to the user
-				event := new(ContractPrintBool)
-				if err := _Contract.contract.UnpackLog(event, "PrintBool", log); err != nil {
-					return err
-				}
-				event.Raw = log
-
-				select {
-				case sink <- event:
-				case err := <-sub.Err():
-					return err
-				case <-quit:
-					return nil
-				}
-			case err := <-sub.Err():
-				return err
-			case <-quit:
-				return nil
-			}
-		}
-	}), nil
-}
-
-// ParsePrintBool is a log parse operation binding the contract event 0x3e1a1345e360785a18ee3f9d25ba93d69ea5c3b5772b44df827662204209612c.
-//
-// Solidity: event PrintBool(bool a)
-func (_Contract *ContractFilterer) ParsePrintBool(log types.Log) (*ContractPrintBool, error) {
-	event := new(ContractPrintBool)
-	if err := _Contract.contract.UnpackLog(event, "PrintBool", log); err != nil {
-		return nil, err
-	}
-	event.Raw = log
-	return event, nil
-}
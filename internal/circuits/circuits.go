@@ -0,0 +1,191 @@
+// Package circuits holds the example circuits for which internal/main.go generates
+// a verifier, with their witnesses, and the setup and proving steps common to the
+// harnesses verifying their proofs.
+package circuits
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend/plonk"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/plonk-solidity/srs"
+)
+
+// Example is an example circuit.
+type Example struct {
+
+	// Name of the example, e.g. com-fiat-shamir
+	Name string
+
+	// Circuit returns the circuit to compile
+	Circuit func() frontend.Circuit
+
+	// Assignment returns the fixed valid assignment used by internal/main.go
+	Assignment func() frontend.Circuit
+
+	// RandomAssignment returns a random valid assignment
+	RandomAssignment func(rng *rand.Rand) frontend.Circuit
+}
+
+// Examples lists the example circuits.
+var Examples = []Example{
+	{
+		Name:    "sb-fiat-shamir",
+		Circuit: func() frontend.Circuit { return &SbFiatShamir{} },
+		Assignment: func() frontend.Circuit {
+			var c SbFiatShamir
+			c.X, c.Y = fixedPermutation()
+			return &c
+		},
+		RandomAssignment: func(rng *rand.Rand) frontend.Circuit {
+			var c SbFiatShamir
+			c.X, c.Y = randomPermutation(rng)
+			return &c
+		},
+	},
+	{
+		Name:    "com-fiat-shamir",
+		Circuit: func() frontend.Circuit { return &ComFiatShamir{} },
+		Assignment: func() frontend.Circuit {
+			var c ComFiatShamir
+			c.X, c.Y = fixedPermutation()
+			return &c
+		},
+		RandomAssignment: func(rng *rand.Rand) frontend.Circuit {
+			var c ComFiatShamir
+			c.X, c.Y = randomPermutation(rng)
+			return &c
+		},
+	},
+	{
+		Name:    "multiple-commitments",
+		Circuit: func() frontend.Circuit { return &MultipleCommitmentCircuit{} },
+		Assignment: func() frontend.Circuit {
+			return &MultipleCommitmentCircuit{X: 2, Y: 3}
+		},
+		RandomAssignment: func(rng *rand.Rand) frontend.Circuit {
+			// Y is different from the last commitment with overwhelming probability
+			return &MultipleCommitmentCircuit{X: randomFr(rng), Y: randomFr(rng)}
+		},
+	},
+}
+
+// Names returns the names of the examples, separated by commas.
+func Names() string {
+	names := make([]string, len(Examples))
+	for i := range Examples {
+		names[i] = Examples[i].Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// Get returns the example called name.
+func Get(name string) (*Example, error) {
+	for i := range Examples {
+		if Examples[i].Name == name {
+			return &Examples[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown circuit %q, expected one of %s", name, Names())
+}
+
+// Setup is a compiled example with its PLONK keys.
+type Setup struct {
+	CCS constraint.ConstraintSystem
+	PK  plonk.ProvingKey
+	VK  *bn254plonk.VerifyingKey
+}
+
+// Setup compiles the circuit and runs the PLONK setup with a test SRS.
+func (e *Example) Setup() (*Setup, error) {
+	return e.SetupWithSRS(nil)
+}
+
+// SetupWithSRS compiles the circuit and runs the PLONK setup with the SRS read
+// by readSRS, which receives the number of G1 powers the setup needs (see
+// srs.Size). The test SRS is used if readSRS is nil.
+func (e *Example) SetupWithSRS(readSRS func(size uint64) (kzg.SRS, error)) (*Setup, error) {
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, e.Circuit())
+	if err != nil {
+		return nil, err
+	}
+
+	var kzgSRS kzg.SRS
+	if readSRS == nil {
+		kzgSRS, err = test.NewKZGSRS(ccs)
+	} else {
+		kzgSRS, err = readSRS(srs.Size(ccs.GetNbConstraints(), ccs.GetNbPublicVariables()))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pk, vk, err := plonk.Setup(ccs, kzgSRS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Setup{CCS: ccs, PK: pk, VK: vk.(*bn254plonk.VerifyingKey)}, nil
+}
+
+// Prove proves assignment, checks the proof with plonk.Verify, and returns it
+// with the public inputs.
+func (s *Setup) Prove(assignment frontend.Circuit) (*bn254plonk.Proof, fr.Vector, error) {
+
+	witnessFull, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, nil, err
+	}
+	witnessPublic, err := witnessFull.Public()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proof, err := plonk.Prove(s.CCS, s.PK, witnessFull)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := plonk.Verify(proof, s.VK, witnessPublic); err != nil {
+		return nil, nil, err
+	}
+
+	return proof.(*bn254plonk.Proof), witnessPublic.Vector().(fr.Vector), nil
+}
+
+// fixedPermutation returns X = [9, .., 18] and Y = [10, .., 19]
+func fixedPermutation() (x, y [10]frontend.Variable) {
+	for i := 0; i < len(x); i++ {
+		x[i] = i + 9 // the circuit adds 1 to the X's, and checks that the X's appear exactly once in Y
+		y[i] = i + 10
+	}
+	return
+}
+
+// randomPermutation returns random X's, and Y a random permutation of the X's
+// plus 1
+func randomPermutation(rng *rand.Rand) (x, y [10]frontend.Variable) {
+	perm := rng.Perm(len(x))
+	for i := 0; i < len(x); i++ {
+		xi := randomFr(rng)
+		x[i] = xi
+		y[perm[i]] = new(big.Int).Add(xi, big.NewInt(1))
+	}
+	return
+}
+
+// randomFr returns a random element of Fr, as a *big.Int
+func randomFr(rng *rand.Rand) *big.Int {
+	return new(big.Int).Rand(rng, fr.Modulus())
+}
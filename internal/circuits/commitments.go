@@ -0,0 +1,44 @@
+package circuits
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// ------------------------------------------
+// multiple commitments
+
+type MultipleCommitmentCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *MultipleCommitmentCircuit) Define(api frontend.API) error {
+
+	a := api.Mul(c.X, c.X, c.X)
+
+	committer, ok := api.(frontend.Committer)
+	if !ok {
+		return fmt.Errorf("type %T doesn't impl the Committer interface", api)
+	}
+
+	b, err := committer.Commit(a)
+	if err != nil {
+		return err
+	}
+
+	d, err := committer.Commit(b)
+	if err != nil {
+		return err
+	}
+
+	e, err := committer.Commit(a, b, d)
+	if err != nil {
+		return err
+	}
+
+	api.AssertIsDifferent(e, c.Y)
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package circuits
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// ------------------------------------------
+// school book Fiat Shamir
+type SbFiatShamir struct {
+	X [10]frontend.Variable
+	Y [10]frontend.Variable `gnark:",public"`
+}
+
+// The circuit generates a sequence of values from its private inputs X.
+// Namely it adds 1 to every private inputs, the resulting set of values is stored
+// in vals. The goal of the circuit is to ensure
+// that the values in vals appear exactly once in the list of the given public values.
+// For instance if Y = [3,4,5,6,7,8,9,10,11,12] then the circuit ensures that vals contains
+// exactly one of each value in Y.
+// To do that, we check the following identity:
+// Π_{i<10}(Yᵢ-x) ==? Π_{i<10}(Xᵢ-x) where x is derived using Fiat Shamir with hash.
+func (c *SbFiatShamir) Define(api frontend.API) error {
+
+	// 1 - generate the values vals (here we add 1 to the private inputs to
+	// simulate a real operation but it could be anything)
+	vals := make([]frontend.Variable, 10)
+	for i := 0; i < len(c.X); i++ {
+		vals[i] = api.Add(c.X[i], 1)
+	}
+
+	// 2 - generate the challenge using Fiat Shamir + mimc
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	tsSnark := fiatshamir.NewTranscript(api, &h, "x")
+	if err := tsSnark.Bind("x", vals[:]); err != nil {
+		return err
+	}
+	if err := tsSnark.Bind("x", c.Y[:]); err != nil {
+		return err
+	}
+	x, err := tsSnark.ComputeChallenge("x")
+	if err != nil {
+		return err
+	}
+
+	// 3 - compute both products Π_{i<10}(Yᵢ-x) and Π_{i<10}(Xᵢ-x)
+	var rhs, lhs, tmp frontend.Variable
+	rhs = 1
+	lhs = 1
+	for i := 0; i < len(vals); i++ {
+
+		tmp = api.Sub(x, vals[i])
+		lhs = api.Mul(lhs, tmp)
+
+		tmp = api.Sub(x, c.Y[i])
+		rhs = api.Mul(rhs, tmp)
+	}
+
+	api.AssertIsEqual(rhs, lhs)
+
+	return nil
+}
+
+// ------------------------------------------
+// Fiat Shamir using commitment
+type ComFiatShamir struct {
+	X [10]frontend.Variable
+	Y [10]frontend.Variable `gnark:",public"`
+}
+
+// The circuit generates a sequence of values from its private inputs X.
+// Namely it adds 1 to every private inputs, the resulting set of values is stored
+// in vals. The goal of the circuit is to ensure
+// that the values in vals appear exactly once in the list of the given public values.
+// For instance if Y = [3,4,5,6,7,8,9,10,11,12] then the circuit ensures that vals contains
+// exactly one of each value in Y.
+// To do that, we check the following identity:
+// Π_{i<10}(Yᵢ-x) ==? Π_{i<10}(Xᵢ-x) where x is derived using Fiat Shamir with hash.
+func (c *ComFiatShamir) Define(api frontend.API) error {
+
+	// 1 - generate the values vals (here we add 1 to the private inputs to
+	// simulate a real operation but it could be anything)
+	vals := make([]frontend.Variable, 10)
+	for i := 0; i < len(c.X); i++ {
+		vals[i] = api.Add(c.X[i], 1)
+	}
+
+	// 2 - generate the challenge using Commit api
+	committer, ok := api.(frontend.Committer)
+	if !ok {
+		return fmt.Errorf("type %T doesn't impl the Committer interface", api)
+	}
+	args := make([]frontend.Variable, len(c.X)+len(vals))
+	copy(args, vals[:])
+	copy(args[len(vals):], c.Y[:])
+	x, err := committer.Commit(args)
+	if err != nil {
+		return err
+	}
+
+	// 3 - compute both products Π_{i<10}(Yᵢ-x) and Π_{i<10}(Xᵢ-x)
+	var rhs, lhs, tmp frontend.Variable
+	rhs = 1
+	lhs = 1
+	for i := 0; i < len(vals); i++ {
+
+		tmp = api.Sub(x, vals[i])
+		lhs = api.Mul(lhs, tmp)
+
+		tmp = api.Sub(x, c.Y[i])
+		rhs = api.Mul(rhs, tmp)
+	}
+
+	api.AssertIsEqual(rhs, lhs)
+
+	return nil
+}
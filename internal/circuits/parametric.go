@@ -0,0 +1,89 @@
+package circuits
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// ------------------------------------------
+// parametric circuit, for the gas benchmarks
+
+// ParametricCircuit has len(Y) public inputs Yᵢ = X+i, NbCommitments BSB22
+// commitments, and NbSquarings squarings of X to fill the domain.
+type ParametricCircuit struct {
+	X frontend.Variable
+	Y []frontend.Variable `gnark:",public"`
+
+	NbCommitments int `gnark:"-"`
+	NbSquarings   int `gnark:"-"`
+}
+
+func (c *ParametricCircuit) Define(api frontend.API) error {
+
+	for i := range c.Y {
+		api.AssertIsEqual(api.Add(c.X, i), c.Y[i])
+	}
+
+	acc := c.X
+	if c.NbCommitments > 0 {
+		committer, ok := api.(frontend.Committer)
+		if !ok {
+			return fmt.Errorf("type %T doesn't impl the Committer interface", api)
+		}
+		for i := 0; i < c.NbCommitments; i++ {
+			com, err := committer.Commit(acc)
+			if err != nil {
+				return err
+			}
+			acc = api.Mul(acc, com)
+		}
+	}
+
+	for i := 0; i < c.NbSquarings; i++ {
+		acc = api.Mul(acc, acc)
+	}
+
+	return nil
+}
+
+// Parametric returns a circuit with nbPublicInputs public inputs and nbCommitments
+// BSB22 commitments, whose domain has 2^logSize elements if they fit in half of
+// it. Otherwise the domain is the smallest one holding them.
+func Parametric(logSize, nbPublicInputs, nbCommitments int) Example {
+
+	// more than half of the domain is used, so that its size is 2^logSize
+	nbSquarings := 1<<(logSize-1) + 1 - nbPublicInputs - 3*nbCommitments
+	if nbSquarings < 0 {
+		nbSquarings = 0
+	}
+
+	circuit := func() *ParametricCircuit {
+		return &ParametricCircuit{
+			Y:             make([]frontend.Variable, nbPublicInputs),
+			NbCommitments: nbCommitments,
+			NbSquarings:   nbSquarings,
+		}
+	}
+	assignment := func(x *big.Int) frontend.Circuit {
+		c := circuit()
+		c.X = x
+		for i := range c.Y {
+			c.Y[i] = new(big.Int).Add(x, big.NewInt(int64(i)))
+		}
+		return c
+	}
+
+	return Example{
+		Name:    fmt.Sprintf("parametric-2^%d-%dpi-%dcom", logSize, nbPublicInputs, nbCommitments),
+		Circuit: func() frontend.Circuit { return circuit() },
+		Assignment: func() frontend.Circuit {
+			return assignment(big.NewInt(3))
+		},
+		RandomAssignment: func(rng *rand.Rand) frontend.Circuit {
+			return assignment(randomFr(rng))
+		},
+	}
+}
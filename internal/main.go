@@ -1,19 +1,20 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"math/big"
 	"os"
 
-	"github.com/consensys/gnark-crypto/ecc"
-	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
-	"github.com/consensys/gnark/backend/plonk"
-	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
-	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/frontend/cs/scs"
-	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
-	"github.com/consensys/gnark/std/hash/mimc"
-	"github.com/consensys/gnark/test"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/debug"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/shadow"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/srs"
 	"github.com/consensys/plonk-solidity/tmpl"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 func checkError(err error) {
@@ -23,273 +24,72 @@ func checkError(err error) {
 	}
 }
 
-// ------------------------------------------
-// school book Fiat Shamir
-type SbFiatShamir struct {
-	X [10]frontend.Variable
-	Y [10]frontend.Variable `gnark:",public"`
-}
-
-// The circuit generates a sequence of values from its private inputs X.
-// Namely it adds 1 to every private inputs, the resulting set of values is stored
-// in vals. The goal of the circuit is to ensure
-// that the values in vals appear exactly once in the list of the given public values.
-// For instance if Y = [3,4,5,6,7,8,9,10,11,12] then the circuit ensures that vals contains
-// exactly one of each value in Y.
-// To do that, we check the following identity:
-// Π_{i<10}(Yᵢ-x) ==? Π_{i<10}(Xᵢ-x) where x is derived using Fiat Shamir with hash.
-func (c *SbFiatShamir) Define(api frontend.API) error {
-
-	// 1 - generate the values vals (here we add 1 to the private inputs to
-	// simulate a real operation but it could be anything)
-	vals := make([]frontend.Variable, 10)
-	for i := 0; i < len(c.X); i++ {
-		vals[i] = api.Add(c.X[i], 1)
-	}
-
-	// 2 - generate the challenge using Fiat Shamir + mimc
-	h, err := mimc.NewMiMC(api)
-	if err != nil {
-		return err
-	}
-	tsSnark := fiatshamir.NewTranscript(api, &h, "x")
-	if err := tsSnark.Bind("x", vals[:]); err != nil {
-		return err
-	}
-	if err := tsSnark.Bind("x", c.Y[:]); err != nil {
-		return err
-	}
-	x, err := tsSnark.ComputeChallenge("x")
-	if err != nil {
-		return err
-	}
-
-	// 3 - compute both products Π_{i<10}(Yᵢ-x) and Π_{i<10}(Xᵢ-x)
-	var rhs, lhs, tmp frontend.Variable
-	rhs = 1
-	lhs = 1
-	for i := 0; i < len(vals); i++ {
-
-		tmp = api.Sub(x, vals[i])
-		lhs = api.Mul(lhs, tmp)
-
-		tmp = api.Sub(x, c.Y[i])
-		rhs = api.Mul(rhs, tmp)
-	}
-
-	api.AssertIsEqual(rhs, lhs)
-
-	return nil
-}
+//go:generate go run main.go
+func main() {
 
-func getVkProofSbFiatShamir() (bn254plonk.Proof, bn254plonk.VerifyingKey, []fr.Element) {
+	circuit := flag.String("circuit", "com-fiat-shamir", "example circuit to generate the verifier of: "+circuits.Names())
+	noBindings := flag.Bool("no-bindings", false, "don't compile TestVerifier.sol nor regenerate abi and gopkg")
+	solcPath := flag.String("solc", "solc", "path of the solc binary")
+	ptau := flag.String("ptau", "", "powers of tau transcript (.ptau) of the SRS, gnark's test SRS by default")
+	flag.Parse()
 
-	var circuit SbFiatShamir
-	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &circuit)
+	example, err := circuits.Get(*circuit)
 	checkError(err)
-
-	var witness SbFiatShamir
-	for i := 0; i < len(witness.X); i++ {
-		witness.X[i] = i + 9 // the circuit adds 1 to the X's, and checks that the X's appear exactly once in Y
-		witness.Y[i] = i + 10
+	var readSRS func(uint64) (kzg.SRS, error)
+	var transcript *srs.Transcript
+	if *ptau != "" {
+		readSRS = func(size uint64) (kzg.SRS, error) {
+			f, err := os.Open(*ptau)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			if transcript, err = srs.ReadPtau(f, size); err != nil {
+				return nil, err
+			}
+			return transcript.SRS(), nil
+		}
 	}
-	witnessFull, err := frontend.NewWitness(&witness, ecc.BN254.ScalarField())
-	checkError(err)
-	witnessPublic, err := witnessFull.Public()
-	checkError(err)
-
-	srs, err := test.NewKZGSRS(ccs)
-	checkError(err)
-
-	pk, vk, err := plonk.Setup(ccs, srs)
-	checkError(err)
-
-	proof, err := plonk.Prove(ccs, pk, witnessFull)
+	setup, err := example.SetupWithSRS(readSRS)
 	checkError(err)
-
-	err = plonk.Verify(proof, vk, witnessPublic)
+	proof, pi, err := setup.Prove(example.Assignment())
 	checkError(err)
+	vk := setup.VK
 
-	tvk := vk.(*bn254plonk.VerifyingKey)
-	tproof := proof.(*bn254plonk.Proof)
-
-	ipi := witnessPublic.Vector()
-	pi := ipi.(fr.Vector)
-
-	return *tproof, *tvk, pi
-}
-
-// ------------------------------------------
-// Fiat Shamir using commitment
-type ComFiatShamir struct {
-	X [10]frontend.Variable
-	Y [10]frontend.Variable `gnark:",public"`
-}
-
-// The circuit generates a sequence of values from its private inputs X.
-// Namely it adds 1 to every private inputs, the resulting set of values is stored
-// in vals. The goal of the circuit is to ensure
-// that the values in vals appear exactly once in the list of the given public values.
-// For instance if Y = [3,4,5,6,7,8,9,10,11,12] then the circuit ensures that vals contains
-// exactly one of each value in Y.
-// To do that, we check the following identity:
-// Π_{i<10}(Yᵢ-x) ==? Π_{i<10}(Xᵢ-x) where x is derived using Fiat Shamir with hash.
-func (c *ComFiatShamir) Define(api frontend.API) error {
-
-	// 1 - generate the values vals (here we add 1 to the private inputs to
-	// simulate a real operation but it could be anything)
-	vals := make([]frontend.Variable, 10)
-	for i := 0; i < len(c.X); i++ {
-		vals[i] = api.Add(c.X[i], 1)
-	}
-
-	// 2 - generate the challenge using Commit api
-	committer, ok := api.(frontend.Committer)
-	if !ok {
-		return fmt.Errorf("type %T doesn't impl the Committer interface", api)
+	// the generated files are not checked in, the folders may not exist yet
+	for _, folder := range []string{"../contracts", "../abi", "../gopkg"} {
+		checkError(os.MkdirAll(folder, 0755))
 	}
-	args := make([]frontend.Variable, len(c.X)+len(vals))
-	copy(args, vals[:])
-	copy(args[len(vals):], c.Y[:])
-	x, err := committer.Commit(args)
-	if err != nil {
-		return err
-	}
-
-	// 3 - compute both products Π_{i<10}(Yᵢ-x) and Π_{i<10}(Xᵢ-x)
-	var rhs, lhs, tmp frontend.Variable
-	rhs = 1
-	lhs = 1
-	for i := 0; i < len(vals); i++ {
-
-		tmp = api.Sub(x, vals[i])
-		lhs = api.Mul(lhs, tmp)
-
-		tmp = api.Sub(x, c.Y[i])
-		rhs = api.Mul(rhs, tmp)
-	}
-
-	api.AssertIsEqual(rhs, lhs)
-
-	return nil
-}
-
-func getVkProofComFiatShamir() (bn254plonk.Proof, bn254plonk.VerifyingKey, []fr.Element) {
-
-	var circuit ComFiatShamir
-	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &circuit)
-	checkError(err)
 
-	var witness ComFiatShamir
-	for i := 0; i < len(witness.X); i++ {
-		witness.X[i] = i + 9 // the circuit adds 1 to the X's, and checks that the X's appear exactly once in Y
-		witness.Y[i] = i + 10
+	// the ABI, bytecode and Go bindings of TestVerifier, in place of make all
+	var bindings *tmpl.Bindings
+	if !*noBindings {
+		bindings = &tmpl.Bindings{
+			Solc:      solc.Options{Path: *solcPath},
+			GoFile:    "../gopkg/contract.go",
+			ABIFolder: "../abi",
+		}
 	}
-	witnessFull, err := frontend.NewWitness(&witness, ecc.BN254.ScalarField())
+	// the test SRS is only for the examples, never for a deployed verifier
+	opts := tmpl.Options{SRS: transcript, AllowTestSRS: transcript == nil}
+	err = tmpl.GenerateVerifierWithOptions(*vk, *proof, pi, "../contracts", opts, bindings)
 	checkError(err)
-	witnessPublic, err := witnessFull.Public()
-	checkError(err)
-
-	srs, err := test.NewKZGSRS(ccs)
-	checkError(err)
-
-	pk, vk, err := plonk.Setup(ccs, srs)
-	checkError(err)
-
-	proof, err := plonk.Prove(ccs, pk, witnessFull)
-	checkError(err)
-
-	err = plonk.Verify(proof, vk, witnessPublic)
-	checkError(err)
-
-	tvk := vk.(*bn254plonk.VerifyingKey)
-	tproof := proof.(*bn254plonk.Proof)
-
-	ipi := witnessPublic.Vector()
-	pi := ipi.(fr.Vector)
-
-	return *tproof, *tvk, pi
-}
-
-// ------------------------------------------
-// multiple commitments
-
-type multipleCommitmentCircuit struct {
-	X frontend.Variable
-	Y frontend.Variable `gnark:",public"`
-}
-
-func (c *multipleCommitmentCircuit) Define(api frontend.API) error {
-
-	a := api.Mul(c.X, c.X, c.X)
-
-	committer, ok := api.(frontend.Committer)
-	if !ok {
-		return fmt.Errorf("type %T doesn't impl the Committer interface", api)
-	}
-
-	b, err := committer.Commit(a)
-	if err != nil {
-		return err
-	}
-
-	d, err := committer.Commit(b)
-	if err != nil {
-		return err
-	}
 
-	e, err := committer.Commit(a, b, d)
-	if err != nil {
-		return err
+	// calldata of test_verifier_go with the same proof and public inputs
+	publicInputs := make([]*big.Int, len(pi))
+	for i := range pi {
+		publicInputs[i] = new(big.Int)
+		pi[i].BigInt(publicInputs[i])
 	}
-
-	api.AssertIsDifferent(e, c.Y)
-
-	return nil
-}
-
-func getVkProofmultipleCommitmentCircuit() (bn254plonk.Proof, bn254plonk.VerifyingKey, []fr.Element) {
-
-	var circuit multipleCommitmentCircuit
-	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &circuit)
+	data, err := calldata.EncodeVerifyCall("test_verifier_go", calldata.MarshalProof(*proof), publicInputs)
 	checkError(err)
-
-	var witness multipleCommitmentCircuit
-	witness.X = 2
-	witness.Y = 3
-	witnessFull, err := frontend.NewWitness(&witness, ecc.BN254.ScalarField())
-	checkError(err)
-	witnessPublic, err := witnessFull.Public()
-	checkError(err)
-
-	srs, err := test.NewKZGSRS(ccs)
-	checkError(err)
-
-	pk, vk, err := plonk.Setup(ccs, srs)
+	err = os.WriteFile("../contracts/TestVerifier.calldata", []byte(hexutil.Encode(data)), 0644)
 	checkError(err)
 
-	proof, err := plonk.Prove(ccs, pk, witnessFull)
-	checkError(err)
-
-	err = plonk.Verify(proof, vk, witnessPublic)
-	checkError(err)
-
-	tvk := vk.(*bn254plonk.VerifyingKey)
-	tproof := proof.(*bn254plonk.Proof)
-
-	ipi := witnessPublic.Vector()
-	pi := ipi.(fr.Vector)
-
-	return *tproof, *tvk, pi
-}
-
-//go:generate go run main.go
-func main() {
-
-	proof, vk, pi := getVkProofComFiatShamir()
-	// proof, vk, pi := getVkProofSbFiatShamir()
-
-	err := tmpl.GenerateVerifier(vk, proof, pi, "../contracts")
+	// gnark accepted the proof, so must the Go mirror of Verifier.sol. Its states
+	// are the reference for the ones logged by a verifier generated in debug mode.
+	states, err := shadow.Verify(vk, calldata.MarshalProof(*proof), publicInputs)
 	checkError(err)
+	checkError(debug.Print(os.Stdout, states))
 
 }
@@ -1,25 +1,40 @@
+// Command main generates the verifier and TestVerifier.sol of an example circuit,
+// compiles them with the local solc, deploys TestVerifier on a simulated backend
+// and calls test_verifier(), which emits the result of the verification.
+//
+// Usage:
+//
+//	go run main.go [-circuit com-fiat-shamir] [-solc solc] [-debug]
+//
+// It then traces a call with a wrong public input with debug.Tracer, which
+// prints the states of Verify up to the phase which fails.
 package main
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"flag"
 	"fmt"
-	"log"
 	"math/big"
 	"os"
 	"strings"
 
-	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
-	contract "github.com/consensys/plonk-solidity/gopkg"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/debug"
+	"github.com/consensys/plonk-solidity/evmtest"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// gasLimit of the calls, the default of evmtest is too low for 3 commitments
+const gasLimit = 5000000
+
 func checkError(err error) {
 	if err != nil {
 		fmt.Println(err)
@@ -27,197 +42,122 @@ func checkError(err error) {
 	}
 }
 
-func createSimulatedBackend(privateKey *ecdsa.PrivateKey) (*backends.SimulatedBackend, *bind.TransactOpts, error) {
-
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(1337))
-	if err != nil {
-		return nil, nil, err
-	}
-
-	balance := new(big.Int)
-	balance.SetString("10000000000000000000", 10) // 10 eth in wei
-
-	address := auth.From
-	genesisAlloc := map[common.Address]core.GenesisAccount{
-		address: {
-			Balance: balance,
-		},
-	}
-
-	// create simulated backend & deploy the contract
-	blockGasLimit := uint64(14712388)
-	client := backends.NewSimulatedBackend(genesisAlloc, blockGasLimit)
-
-	return client, auth, nil
-
-}
-
-func getTransactionOpts(privateKey *ecdsa.PrivateKey, auth *bind.TransactOpts, client *backends.SimulatedBackend) (*bind.TransactOpts, error) {
-
-	fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
-	nonce, err := client.PendingNonceAt(context.Background(), fromAddress)
-	if err != nil {
-		return nil, err
-	}
-
-	gasprice, err := client.SuggestGasPrice(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)
-	auth.GasLimit = uint64(500000) // -> + add the require for the pairing... +20k
-	auth.GasPrice = gasprice
-
-	return auth, nil
-
-}
-
-func serialiseProof(proof bn254plonk.Proof) []byte {
-
-	var res []byte
-
-	// uint256 l_com_x;
-	// uint256 l_com_y;
-	// uint256 r_com_x;
-	// uint256 r_com_y;
-	// uint256 o_com_x;
-	// uint256 o_com_y;
-	var tmp64 [64]byte
-	for i := 0; i < 3; i++ {
-		tmp64 = proof.LRO[i].RawBytes()
-		res = append(res, tmp64[:]...)
-	}
-
-	// uint256 h_0_x;
-	// uint256 h_0_y;
-	// uint256 h_1_x;
-	// uint256 h_1_y;
-	// uint256 h_2_x;
-	// uint256 h_2_y;
-	for i := 0; i < 3; i++ {
-		tmp64 = proof.H[i].RawBytes()
-		res = append(res, tmp64[:]...)
-	}
-	var tmp32 [32]byte
-
-	// uint256 l_at_zeta;
-	// uint256 r_at_zeta;
-	// uint256 o_at_zeta;
-	// uint256 s1_at_zeta;
-	// uint256 s2_at_zeta;
-	for i := 2; i < 7; i++ {
-		tmp32 = proof.BatchedProof.ClaimedValues[i].Bytes()
-		res = append(res, tmp32[:]...)
-	}
+func main() {
 
-	// uint256 grand_product_commitment_x;
-	// uint256 grand_product_commitment_y;
-	tmp64 = proof.Z.RawBytes()
-	res = append(res, tmp64[:]...)
-
-	// uint256 grand_product_at_zeta_omega;
-	tmp32 = proof.ZShiftedOpening.ClaimedValue.Bytes()
-	res = append(res, tmp32[:]...)
-
-	// uint256 quotient_polynomial_at_zeta;
-	// uint256 linearization_polynomial_at_zeta;
-	tmp32 = proof.BatchedProof.ClaimedValues[0].Bytes()
-	res = append(res, tmp32[:]...)
-	tmp32 = proof.BatchedProof.ClaimedValues[1].Bytes()
-	res = append(res, tmp32[:]...)
-
-	// uint256 opening_at_zeta_proof_x;
-	// uint256 opening_at_zeta_proof_y;
-	tmp64 = proof.BatchedProof.H.RawBytes()
-	res = append(res, tmp64[:]...)
-
-	// uint256 opening_at_zeta_omega_proof_x;
-	// uint256 opening_at_zeta_omega_proof_y;
-	tmp64 = proof.ZShiftedOpening.H.RawBytes()
-	res = append(res, tmp64[:]...)
-
-	// uint256[] selector_commit_api_at_zeta;
-	// uint256[] wire_committed_commitments;
-	for i := 7; i < len(proof.BatchedProof.ClaimedValues); i++ {
-		tmp32 = proof.BatchedProof.ClaimedValues[i].Bytes()
-		res = append(res, tmp32[:]...)
-	}
-	for i := 0; i < len(proof.Bsb22Commitments); i++ {
-		tmp64 = proof.Bsb22Commitments[i].RawBytes()
-		res = append(res, tmp64[:]...)
-	}
+	circuit := flag.String("circuit", "com-fiat-shamir", "example circuit to verify: "+circuits.Names())
+	solcPath := flag.String("solc", "solc", "path of the solc binary")
+	debugMode := flag.Bool("debug", false, "generate the verifier in debug mode, to print the states it logs")
+	flag.Parse()
 
-	return res
-}
+	ctx := context.Background()
 
-func main() {
+	// prove the example, and generate its verifier and TestVerifier.sol
+	example, err := circuits.Get(*circuit)
+	checkError(err)
+	setup, err := example.Setup()
+	checkError(err)
+	proof, pi, err := setup.Prove(example.Assignment())
+	checkError(err)
 
-	// create account
-	privateKey, err := crypto.GenerateKey()
-	if err != nil {
-		log.Fatal(err)
+	// the examples are set up with the test SRS
+	opts := tmpl.Options{AllowTestSRS: true}
+	if *debugMode {
+		opts.Mode = tmpl.Debug
 	}
-
-	// create simulated backend
-	client, auth, err := createSimulatedBackend(privateKey)
+	sources, err := tmpl.GenerateSources(*setup.VK, *proof, pi, opts)
 	checkError(err)
-
-	// deploy the contract
-	contractAddress, _, instance, err := contract.DeployContract(auth, client)
+	contracts, err := solc.Compile(sources, solc.Options{Path: *solcPath})
+	checkError(err)
+	testVerifier, err := solc.Find(contracts, "TestVerifier")
+	checkError(err)
+	contractABI, err := abi.JSON(strings.NewReader(testVerifier.ABI))
 	checkError(err)
-	client.Commit()
 
-	// Interact with the contract
-	auth, err = getTransactionOpts(privateKey, auth, client)
+	// create simulated backend, and deploy the contract
+	backend, err := evmtest.NewBackend(evmtest.WithGasLimit(gasLimit))
+	checkError(err)
+	defer backend.Close()
+	client := backend.Client
+	v, err := backend.Deploy(ctx, testVerifier.Bin, evmtest.WithMethod("test_verifier_go"))
 	checkError(err)
 
 	// should output true: proof and public in puts are correct
-	_, err = instance.TestVerifier(auth)
+	_, _, err = v.TransactRaw(ctx, crypto.Keccak256([]byte("test_verifier()"))[:4])
 	checkError(err)
-	client.Commit()
-
-	// should output nothing: the contract reverts
-	// _, err = instance.TestVerifierProofPointNotOnCurve(auth)
-	// checkError(err)
-	// client.Commit()
-
-	// should output false: ECMUL fails
-	// _, err = instance.TestVerifierProofScalarBiggerThanR(auth)
-	// checkError(err)
-	// client.Commit()
-
-	// should output false: the proof is tampered
-	// _, err = instance.TestVerifierProofWrongPoint(auth)
-	// checkError(err)
-	// client.Commit()
-
-	// should output false: the public inputs are wrong
-	// _, err = instance.TestVerifierProofWrongPublicInput(auth)
-	// checkError(err)
-	// client.Commit()
 
 	// query event
 	query := ethereum.FilterQuery{
 		FromBlock: big.NewInt(0),
-		ToBlock:   big.NewInt(2),
 		Addresses: []common.Address{
-			contractAddress,
+			v.Address,
 		},
 	}
 
-	logs, err := client.FilterLogs(context.Background(), query)
+	logs, err := client.FilterLogs(ctx, query)
 	checkError(err)
 
-	contractABI, err := abi.JSON(strings.NewReader(string(contract.ContractABI)))
-	checkError(err)
+	// states of Verify, logged when the verifier is generated in debug mode
+	var states []debug.State
+	for i := range logs {
+		state, err := debug.DecodeLog(&logs[i])
+		if err == debug.ErrNotDebugLog {
+			continue
+		}
+		checkError(err)
+		states = append(states, state)
+	}
+	checkError(debug.Print(os.Stdout, states))
 
 	for _, vLog := range logs {
 
+		if vLog.Topics[0] != contractABI.Events["PrintBool"].ID {
+			continue
+		}
 		var event interface{}
 		err = contractABI.UnpackIntoInterface(&event, "PrintBool", vLog.Data)
 		checkError(err)
 		fmt.Println(event)
 	}
+
+	// should output the states of Verify up to the phase which fails, found in
+	// the bytecode of TestVerifier with its source map
+	boundaries, err := debug.NewBoundaries(testVerifier, opts.Files().Verifier, sources[opts.Files().Verifier])
+	checkError(err)
+	checkError(traceWrongPublicInput(client, backend.From, v.Address, boundaries, calldata.MarshalProof(*proof), pi))
+}
+
+// traceWrongPublicInput calls test_verifier_go with proof and a wrong public
+// input, under debug.Tracer. The call reverts so its logs are lost, but the
+// tracer captures the state of Verify at the end of each phase and when it
+// reverts.
+func traceWrongPublicInput(client *backends.SimulatedBackend, from, contractAddress common.Address, b *debug.Boundaries, proof []byte, pi fr.Vector) error {
+
+	if len(pi) == 0 {
+		return fmt.Errorf("the circuit has no public input")
+	}
+
+	// x+1 in place of the first public input x
+	wrong := make([]*big.Int, len(pi))
+	for i := range pi {
+		wrong[i] = new(big.Int)
+		pi[i].BigInt(wrong[i])
+	}
+	wrong[0].Add(wrong[0], big.NewInt(1))
+
+	input, err := calldata.EncodeVerifyCall("test_verifier_go", proof, wrong)
+	if err != nil {
+		return err
+	}
+	msg := ethereum.CallMsg{From: from, To: &contractAddress, Data: input}
+	tracer, err := debug.TraceCall(client.Blockchain(), msg, b)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("wrong public input: %v\n", tracer.CallErr)
+
+	states := tracer.States
+	if tracer.Reverted != nil {
+		states = append(states, *tracer.Reverted)
+	}
+	return debug.Print(os.Stdout, states)
 }
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/evmtest"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/reverts"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
+)
+
+// gasLimit of the calls, the default of evmtest is too low for 3 commitments
+const gasLimit = 5000000
+
+// verifierTest is the verifier of an example, generated as a contract and
+// deployed on a simulated backend, with a valid proof
+type verifierTest struct {
+	verifier      *evmtest.Verifier
+	proof         []byte
+	publicInputs  []*big.Int
+	nbCommitments int
+}
+
+// newVerifierTest sets example up with the test SRS, proves its fixed assignment,
+// and deploys its verifier compiled with the solc of $PATH. The test is skipped if
+// there is none.
+func newVerifierTest(t *testing.T, example *circuits.Example) *verifierTest {
+	t.Helper()
+
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip("solc is not in $PATH")
+	}
+
+	setup, err := example.Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, pi, err := setup.Prove(example.Assignment())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sources, err := tmpl.GenerateVerifierSources(*setup.VK, tmpl.Options{Kind: tmpl.Contract, AllowTestSRS: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contracts, err := solc.Compile(sources, solc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract, err := solc.Find(contracts, "PlonkVerifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend, err := evmtest.NewBackend(evmtest.WithGasLimit(gasLimit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { backend.Close() })
+	verifier, err := backend.Deploy(context.Background(), contract.Bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := &verifierTest{
+		verifier:      verifier,
+		proof:         calldata.MarshalProof(*proof),
+		publicInputs:  make([]*big.Int, len(pi)),
+		nbCommitments: len(proof.Bsb22Commitments),
+	}
+	for i := range pi {
+		res.publicInputs[i] = pi[i].BigInt(new(big.Int))
+	}
+	return res
+}
+
+// malformedCall is a call to Verify with malformed arguments, which must revert
+// with err
+type malformedCall struct {
+	name  string
+	proof []byte
+	pi    []*big.Int
+	err   *reverts.VerifierError
+}
+
+func TestMalformedCalls(t *testing.T) {
+
+	for i := range circuits.Examples {
+		example := &circuits.Examples[i]
+		t.Run(example.Name, func(t *testing.T) {
+
+			ctx := context.Background()
+			v := newVerifierTest(t, example)
+			proof, pi := v.proof, v.publicInputs
+
+			if ok, _, err := v.verifier.Call(ctx, proof, pi); !ok {
+				t.Fatalf("the valid call is rejected: %v", err)
+			}
+
+			size := big.NewInt(int64(len(proof)))
+			nbPublicInputs := big.NewInt(int64(len(pi)))
+			calls := []malformedCall{
+				{"truncated proof", proof[:len(proof)-32], pi,
+					reverts.New("WrongProofSize", big.NewInt(int64(len(proof)-32)), size)},
+				{"padded proof", append(append([]byte{}, proof...), make([]byte, 32)...), pi,
+					reverts.New("WrongProofSize", big.NewInt(int64(len(proof)+32)), size)},
+				{"extra public input", proof, append(append([]*big.Int{}, pi...), big.NewInt(0)),
+					reverts.New("WrongNumberOfPublicInputs", big.NewInt(int64(len(pi)+1)), nbPublicInputs)},
+			}
+			if len(pi) != 0 {
+				// x+r in place of the first public input x
+				shifted := make([]*big.Int, len(pi))
+				copy(shifted, pi)
+				shifted[0] = new(big.Int).Add(pi[0], fr.Modulus())
+				calls = append(calls,
+					malformedCall{"public input not reduced mod r", proof, shifted,
+						reverts.New("PublicInputNotReduced", big.NewInt(0))},
+					malformedCall{"missing public input", proof, pi[:len(pi)-1],
+						reverts.New("WrongNumberOfPublicInputs", big.NewInt(int64(len(pi)-1)), nbPublicInputs)},
+				)
+			}
+
+			for _, w := range calldata.ProofWords(v.nbCommitments) {
+				// the offset of a point is the one of its x coordinate, counted from
+				// the start of the bytes memory proof
+				offset := big.NewInt(int64(w.Field.Offset + 0x20))
+				if w.Coordinate {
+					// x+p or y+p in place of the coordinate
+					calls = append(calls, malformedCall{w.Name + " not reduced mod p", addToWord(proof, w.Offset, fp.Modulus()), pi,
+						reverts.New("ProofCoordinateNotReduced", offset)})
+				} else {
+					// s+r in place of the scalar s
+					calls = append(calls, malformedCall{w.Name + " not reduced mod r", addToWord(proof, w.Offset, fr.Modulus()), pi,
+						reverts.New("ProofScalarNotReduced", offset)})
+				}
+			}
+			for _, f := range calldata.ProofLayout(v.nbCommitments) {
+				if f.Kind != calldata.Point {
+					continue
+				}
+				// (x, y+1) is not on the curve, y+1 = p is replaced by 0
+				y := f.Offset + 32
+				tampered := addToWord(proof, y, big.NewInt(1))
+				if new(big.Int).SetBytes(tampered[y:y+32]).Cmp(fp.Modulus()) == 0 {
+					copy(tampered[y:y+32], make([]byte, 32))
+				}
+				calls = append(calls, malformedCall{strings.TrimSuffix(f.Words()[0].Name, "_x") + " not on Bn254", tampered, pi,
+					reverts.New("ProofPointNotOnCurve", big.NewInt(int64(f.Offset+0x20)))})
+			}
+
+			for _, c := range calls {
+				ok, _, err := v.verifier.Call(ctx, c.proof, c.pi)
+				if ok {
+					t.Errorf("%s: accepted", c.name)
+					continue
+				}
+				if !errors.Is(err, c.err) {
+					t.Errorf("%s: expected %v, got %v", c.name, c.err, err)
+				}
+			}
+		})
+	}
+}
+
+// addToWord returns a copy of proof where the 32 bytes word at offset is
+// increased by v. The result must fit on 32 bytes.
+func addToWord(proof []byte, offset int, v *big.Int) []byte {
+	res := append([]byte{}, proof...)
+	var w big.Int
+	w.SetBytes(proof[offset:offset+32]).Add(&w, v).FillBytes(res[offset : offset+32])
+	return res
+}
+
+// mutatedCall is a call to Verify where a single field of the proof, or a single
+// public input, is changed
+type mutatedCall struct {
+	field    string
+	mutation string
+	proof    []byte
+	pi       []*big.Int
+}
+
+// TestMutations calls Verify with each field of the proof layout, and each public
+// input, mutated in turn:
+//   - the last byte of each word is flipped
+//   - s+1 mod r replaces a scalar s, a canonical scalar
+//   - P+G replaces a point P, where G is the generator of G1, a point on Bn254
+//
+// The last two mutations pass the checks on the encoding of the proof, the
+// verifier can only reject them if the field is bound by the transcript or by
+// the pairing check.
+func TestMutations(t *testing.T) {
+
+	for i := range circuits.Examples {
+		example := &circuits.Examples[i]
+		t.Run(example.Name, func(t *testing.T) {
+
+			ctx := context.Background()
+			v := newVerifierTest(t, example)
+			proof, pi := v.proof, v.publicInputs
+
+			if ok, _, err := v.verifier.Call(ctx, proof, pi); !ok {
+				t.Fatalf("the valid call is rejected: %v", err)
+			}
+
+			var calls []mutatedCall
+			for _, w := range calldata.ProofWords(v.nbCommitments) {
+				flipped := append([]byte{}, proof...)
+				flipped[w.Offset+31] ^= 1
+				calls = append(calls, mutatedCall{w.Name, "last byte flipped", flipped, pi})
+			}
+			_, _, g, _ := bn254.Generators()
+			fields := calldata.ProofLayout(v.nbCommitments)
+			for i := range fields {
+				f := &fields[i]
+				name := f.Words()[0].Name
+				if f.Kind == calldata.Scalar {
+					var s fr.Element
+					s.SetBytes(proof[f.Offset : f.Offset+fr.Bytes])
+					s.Add(&s, new(fr.Element).SetOne())
+					calls = append(calls, mutatedCall{name, "s+1", setWord(proof, f.Offset, s.Bytes()), pi})
+					continue
+				}
+				var p bn254.G1Affine
+				p.X.SetBytes(proof[f.Offset : f.Offset+fp.Bytes])
+				p.Y.SetBytes(proof[f.Offset+fp.Bytes : f.Offset+2*fp.Bytes])
+				p.Add(&p, &g)
+				mutated := setWord(proof, f.Offset, p.X.Bytes())
+				mutated = setWord(mutated, f.Offset+fp.Bytes, p.Y.Bytes())
+				calls = append(calls, mutatedCall{strings.TrimSuffix(name, "_x"), "P+G", mutated, pi})
+			}
+			for i := range pi {
+				var x fr.Element
+				x.SetBigInt(pi[i])
+				x.Add(&x, new(fr.Element).SetOne())
+				mutated := make([]*big.Int, len(pi))
+				copy(mutated, pi)
+				mutated[i] = x.BigInt(new(big.Int))
+				calls = append(calls, mutatedCall{fmt.Sprintf("public input %d", i), "x+1", proof, mutated})
+			}
+
+			for _, c := range calls {
+				if ok, _, _ := v.verifier.Call(ctx, c.proof, c.pi); ok {
+					t.Errorf("%s is not bound: the verifier accepts it with %s", c.field, c.mutation)
+				}
+			}
+		})
+	}
+}
+
+// setWord returns a copy of proof where the 32 bytes word at offset is w
+func setWord(proof []byte, offset int, w [32]byte) []byte {
+	res := append([]byte{}, proof...)
+	copy(res[offset:offset+32], w[:])
+	return res
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"os/exec"
+	"testing"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/evmrun"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/reverts"
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/consensys/plonk-solidity/tmpl"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+var (
+	// brokenAddress holds the code standing for a broken precompile
+	brokenAddress = common.HexToAddress("0xbad")
+
+	// PUSH1 0, PUSH1 0, REVERT
+	revertCode = []byte{0x60, 0x00, 0x60, 0x00, 0xfd}
+
+	// PUSH1 0x20, PUSH1 0, RETURN: 32 zero bytes
+	returnZeroCode = []byte{0x60, 0x20, 0x60, 0x00, 0xf3}
+)
+
+// precompileBreaker is a tracer which breaks the precompile at address: it
+// deploys code at brokenAddress when the call starts, and redirects there each
+// STATICCALL to the precompile. The EVM and its precompiles are left untouched,
+// only the state of the traced call is modified.
+type precompileBreaker struct {
+	address common.Address
+	code    []byte
+}
+
+func (b *precompileBreaker) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	env.StateDB.SetCode(brokenAddress, b.code)
+}
+
+// CaptureState runs before the instruction, once its gas is charged: the
+// address popped by STATICCALL is replaced on the stack
+func (b *precompileBreaker) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if op != vm.STATICCALL || err != nil {
+		return
+	}
+	// staticcall(gas, address, argsOffset, argsSize, retOffset, retSize)
+	if address := scope.Stack.Back(1); common.Address(address.Bytes20()) == b.address {
+		address.SetBytes(brokenAddress.Bytes())
+	}
+}
+
+func (b *precompileBreaker) CaptureTxStart(gasLimit uint64) {}
+
+func (b *precompileBreaker) CaptureTxEnd(restGas uint64) {}
+
+func (b *precompileBreaker) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func (b *precompileBreaker) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+func (b *precompileBreaker) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (b *precompileBreaker) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// TestFailingPrecompiles breaks in turn each precompile used by Verify
+// (sha256, modexp, ecAdd, ecMul, ecPairing), and checks that the valid proof is
+// never accepted: Verify reverts with PrecompileFailed and the address of the
+// precompile, or with PairingCheckFailed if the pairing returns false.
+func TestFailingPrecompiles(t *testing.T) {
+
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip("solc is not in $PATH")
+	}
+
+	testCases := []struct {
+		name    string
+		address byte
+		code    []byte
+		err     *reverts.VerifierError
+	}{
+		{"sha256 fails", 0x02, revertCode, reverts.New("PrecompileFailed", big.NewInt(2))},
+		{"modexp fails", 0x05, revertCode, reverts.New("PrecompileFailed", big.NewInt(5))},
+		{"ecAdd fails", 0x06, revertCode, reverts.New("PrecompileFailed", big.NewInt(6))},
+		{"ecMul fails", 0x07, revertCode, reverts.New("PrecompileFailed", big.NewInt(7))},
+		{"ecPairing fails", 0x08, revertCode, reverts.New("PrecompileFailed", big.NewInt(8))},
+		{"ecPairing returns false", 0x08, returnZeroCode, reverts.New("PairingCheckFailed")},
+	}
+
+	for i := range circuits.Examples {
+		example := &circuits.Examples[i]
+		t.Run(example.Name, func(t *testing.T) {
+
+			setup, err := example.Setup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, pi, err := setup.Prove(example.Assignment())
+			if err != nil {
+				t.Fatal(err)
+			}
+			proofBytes := calldata.MarshalProof(*proof)
+			publicInputs := make([]*big.Int, len(pi))
+			for i := range pi {
+				publicInputs[i] = pi[i].BigInt(new(big.Int))
+			}
+
+			sources, err := tmpl.GenerateVerifierSources(*setup.VK, tmpl.Options{Kind: tmpl.Contract, AllowTestSRS: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			contracts, err := solc.Compile(sources, solc.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifier, err := solc.Find(contracts, "PlonkVerifier")
+			if err != nil {
+				t.Fatal(err)
+			}
+			runner, err := evmrun.Deploy(verifier.Bin)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := runner.Verify("Verify", proofBytes, publicInputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res.Accepted {
+				t.Fatalf("genuine precompiles: the valid proof is rejected: %v", res.Err)
+			}
+
+			for _, tc := range testCases {
+				r := runner.Copy()
+				r.Tracer = &precompileBreaker{address: common.BytesToAddress([]byte{tc.address}), code: tc.code}
+				res, err := r.Verify("Verify", proofBytes, publicInputs)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if res.Accepted {
+					t.Errorf("%s: accepted", tc.name)
+					continue
+				}
+				if !errors.Is(res.Err, tc.err) {
+					t.Errorf("%s: expected %v, got %v", tc.name, tc.err, res.Err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package profile attributes the gas used by Verify to its sections, for a
+// verifier generated in profile mode.
+//
+// In profile mode, Verify emits a PlonkProfileEnter log when it enters a section
+// (a phase of the verification, or a costly helper such as hash_fr or
+// batch_invert) and a PlonkProfileExit log when it leaves it. The logs read no
+// memory, Tracer skips them and charges every other opcode to the sections open
+// when it runs, splitting the gas of the precompile calls by precompile.
+package profile
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Section is a part of Verify delimited by profiling markers.
+type Section uint8
+
+const (
+	// SectionVerify is the whole of Verify, the other sections are nested in it
+	SectionVerify Section = iota
+	SectionCheckInputs
+	SectionDeriveChallenges
+	SectionDeriveGamma
+	SectionDeriveBeta
+	SectionDeriveAlpha
+	SectionDeriveZeta
+	SectionComputePi
+	SectionLagrangesAtZ
+	SectionBatchInvert
+	SectionHashFr
+	SectionAlphaSquareLagrange
+	SectionQuotientEval
+	SectionFoldH
+	SectionLinearisedPolynomial
+	SectionGammaKzg
+	SectionFoldState
+	SectionBatchVerify
+)
+
+// sectionNames are the names of the sections, they match the functions of the
+// verifier they delimit
+var sectionNames = []string{
+	SectionVerify:               "Verify",
+	SectionCheckInputs:          "check_inputs",
+	SectionDeriveChallenges:     "derive_gamma_beta_alpha_zeta",
+	SectionDeriveGamma:          "derive_gamma",
+	SectionDeriveBeta:           "derive_beta",
+	SectionDeriveAlpha:          "derive_alpha",
+	SectionDeriveZeta:           "derive_zeta",
+	SectionComputePi:            "compute_pi",
+	SectionLagrangesAtZ:         "batch_compute_lagranges_at_z",
+	SectionBatchInvert:          "batch_invert",
+	SectionHashFr:               "hash_fr",
+	SectionAlphaSquareLagrange:  "compute_alpha_square_lagrange_0",
+	SectionQuotientEval:         "verify_quotient_poly_eval_at_zeta",
+	SectionFoldH:                "fold_h",
+	SectionLinearisedPolynomial: "compute_commitment_linearised_polynomial",
+	SectionGammaKzg:             "compute_gamma_kzg",
+	SectionFoldState:            "fold_state",
+	SectionBatchVerify:          "batch_verify_multi_points",
+}
+
+// Sections returns every section.
+func Sections() []Section {
+	res := make([]Section, len(sectionNames))
+	for i := range res {
+		res[i] = Section(i)
+	}
+	return res
+}
+
+func (s Section) String() string {
+	if int(s) < len(sectionNames) {
+		return sectionNames[s]
+	}
+	return fmt.Sprintf("Section(%d)", uint8(s))
+}
+
+// EnterEventSignature and ExitEventSignature are the signatures of the events
+// marking the start and the end of a section.
+const (
+	EnterEventSignature = "PlonkProfileEnter(uint256)"
+	ExitEventSignature  = "PlonkProfileExit(uint256)"
+)
+
+// EnterEventID and ExitEventID are the first topics of the profiling logs, the
+// second one is the section.
+var (
+	EnterEventID common.Hash = crypto.Keccak256Hash([]byte(EnterEventSignature))
+	ExitEventID  common.Hash = crypto.Keccak256Hash([]byte(ExitEventSignature))
+)
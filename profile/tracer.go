@@ -0,0 +1,204 @@
+package profile
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Precompiles are the names of the precompiles called by Verify, indexed as in
+// Entry.Precompiles.
+var Precompiles = [...]string{"sha256", "modexp", "ecAdd", "ecMul", "ecPairing"}
+
+var precompileAddresses = [len(Precompiles)]byte{0x02, 0x05, 0x06, 0x07, 0x08}
+
+// Entry is the gas used by a section of Verify.
+type Entry struct {
+	Section Section
+
+	// Level is the number of sections in which the section is nested, 0 for
+	// Verify
+	Level int
+
+	// Calls is the number of times the section was entered
+	Calls int
+
+	// Gas is the gas used by the section, including the sections nested in it,
+	// and Self the part which is not spent in a nested section. The logs of the
+	// markers are not counted, only the pushes of their arguments (12 gas each).
+	Gas  uint64
+	Self uint64
+
+	// Precompiles is the part of Gas spent calling each precompile, in the order
+	// of Precompiles. It includes the cost of the call opcodes.
+	Precompiles [len(Precompiles)]uint64
+}
+
+// Tracer is a vm.EVMLogger attributing the gas used by a verifier generated in
+// profile mode to its sections.
+type Tracer struct {
+	entries map[Section]*Entry
+	order   []Section
+
+	// sections being executed, innermost last
+	open []Section
+
+	// depth of the call running Verify, known once the first section is entered
+	depth int
+
+	// the last opcode run in a section, charged once the gas left after it is
+	// known. precompile is the index of the precompile it calls, -1 if none.
+	pending    bool
+	gas        uint64
+	precompile int
+
+	err error
+}
+
+// NewTracer returns a tracer to pass to vm.Config, the profile is in the tracer
+// once the EVM has run.
+func NewTracer() *Tracer {
+	return &Tracer{entries: make(map[Section]*Entry)}
+}
+
+// Err returns the first error met while reading the markers.
+func (t *Tracer) Err() error {
+	return t.err
+}
+
+// Entries returns the gas used by each section entered, in the order in which
+// they were first entered.
+func (t *Tracer) Entries() []Entry {
+	res := make([]Entry, len(t.order))
+	for i, s := range t.order {
+		res[i] = *t.entries[s]
+	}
+	return res
+}
+
+func (t *Tracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.err != nil || err != nil {
+		return
+	}
+
+	if t.pending && depth == t.depth {
+		t.charge(t.gas - gas)
+	}
+
+	stack := scope.Stack
+	if op == vm.LOG2 {
+		// log2(offset, size, topic0, topic1)
+		topic := common.Hash(stack.Back(2).Bytes32())
+		if topic == EnterEventID || topic == ExitEventID {
+			t.mark(topic == EnterEventID, Section(stack.Back(3).Uint64()), depth)
+			return
+		}
+	}
+
+	if len(t.open) == 0 || depth != t.depth {
+		return
+	}
+	t.pending, t.gas, t.precompile = true, gas, -1
+	if op == vm.STATICCALL || op == vm.CALL {
+		// (static)call(gas, address, ...)
+		address := stack.Back(1).ToBig()
+		for i, a := range precompileAddresses {
+			if address.Cmp(big.NewInt(int64(a))) == 0 {
+				t.precompile = i
+			}
+		}
+	}
+}
+
+// mark enters or exits section
+func (t *Tracer) mark(enter bool, section Section, depth int) {
+	if len(t.open) == 0 {
+		t.depth = depth
+	}
+	if depth != t.depth {
+		t.err = fmt.Errorf("%s marked at depth %d, expected %d", section, depth, t.depth)
+		return
+	}
+
+	if !enter {
+		if len(t.open) == 0 || t.open[len(t.open)-1] != section {
+			t.err = fmt.Errorf("exit of %s outside of it", section)
+			return
+		}
+		t.open = t.open[:len(t.open)-1]
+		return
+	}
+
+	e, ok := t.entries[section]
+	if !ok {
+		e = &Entry{Section: section, Level: len(t.open)}
+		t.entries[section] = e
+		t.order = append(t.order, section)
+	}
+	e.Calls++
+	t.open = append(t.open, section)
+}
+
+// charge charges the pending opcode, which used gas, to the open sections
+func (t *Tracer) charge(gas uint64) {
+	t.pending = false
+	for _, s := range t.open {
+		e := t.entries[s]
+		e.Gas += gas
+		if t.precompile >= 0 {
+			e.Precompiles[t.precompile] += gas
+		}
+	}
+	if len(t.open) > 0 {
+		t.entries[t.open[len(t.open)-1]].Self += gas
+	}
+}
+
+func (t *Tracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *Tracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *Tracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+}
+
+func (t *Tracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func (t *Tracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+func (t *Tracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (t *Tracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// Print writes entries as a table, a nested section is indented below the
+// section containing it. The share of each section is relative to Verify.
+func Print(w io.Writer, entries []Entry) error {
+
+	var total uint64
+	for _, e := range entries {
+		if e.Level == 0 {
+			total += e.Gas
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "section\tcalls\tgas\t%%\tself\t%s\t\n", strings.Join(Precompiles[:], "\t"))
+	for _, e := range entries {
+		share := 0.
+		if total != 0 {
+			share = 100 * float64(e.Gas) / float64(total)
+		}
+		fmt.Fprintf(tw, "%s%s\t%d\t%d\t%.1f\t%d\t", strings.Repeat("  ", e.Level), e.Section, e.Calls, e.Gas, share, e.Self)
+		for _, g := range e.Precompiles {
+			fmt.Fprintf(tw, "%d\t", g)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
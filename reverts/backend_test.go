@@ -0,0 +1,94 @@
+package reverts_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/plonk-solidity/evmtest"
+	"github.com/consensys/plonk-solidity/reverts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// revertingContract returns the creation code of a contract which reverts with
+// data on every call, data being shorter than 256 bytes
+func revertingContract(data []byte) []byte {
+
+	// mstore the data word by word, then revert(0, len(data))
+	var runtime []byte
+	for offset := 0; offset < len(data); offset += 32 {
+		word := make([]byte, 32)
+		copy(word, data[offset:])
+		runtime = append(runtime, byte(vm.PUSH32))
+		runtime = append(runtime, word...)
+		runtime = append(runtime, byte(vm.PUSH1), byte(offset), byte(vm.MSTORE))
+	}
+	runtime = append(runtime, byte(vm.PUSH1), byte(len(data)), byte(vm.PUSH1), 0, byte(vm.REVERT))
+
+	// codecopy(0, len(constructor), len(runtime)), return(0, len(runtime))
+	constructor := []byte{
+		byte(vm.PUSH1), byte(len(runtime)), byte(vm.DUP1),
+		byte(vm.PUSH1), 11, byte(vm.PUSH1), 0, byte(vm.CODECOPY),
+		byte(vm.PUSH1), 0, byte(vm.RETURN),
+	}
+	return append(constructor, runtime...)
+}
+
+// TestSimulatedBackend decodes the revert of a contract deployed on a simulated
+// backend, from the error of an eth_call and from the receipt of a transaction
+func TestSimulatedBackend(t *testing.T) {
+
+	ctx := context.Background()
+	expected := reverts.New("WrongProofSize", big.NewInt(0x300), big.NewInt(0x320))
+	sel := expected.Spec.Selector()
+	data := append(sel[:], make([]byte, 64)...)
+	expected.Args[0].FillBytes(data[4:36])
+	expected.Args[1].FillBytes(data[36:68])
+
+	backend, err := evmtest.NewBackend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	v, err := backend.Deploy(ctx, revertingContract(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// eth_call, the error is decoded with FromCallError
+	if ok, _, err := v.CallRaw(ctx, nil); ok || !errors.Is(err, expected) {
+		t.Errorf("call: expected %v, got %v", expected, err)
+	}
+
+	// transaction, the revert is replayed by FromReceipt
+	auth, err := backend.TransactOpts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := bind.NewBoundContract(v.Address, abi.ABI{}, backend.Client, backend.Client, backend.Client)
+	tx, err := contract.RawTransact(auth, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Client.Commit()
+	receipt, err := backend.Client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("the transaction didn't revert")
+	}
+	err = reverts.FromReceipt(ctx, backend.Client, backend.From, tx, receipt)
+	if !errors.Is(err, expected) || !errors.Is(err, reverts.ErrMalformedCalldata) {
+		t.Errorf("receipt: expected %v, got %v", expected, err)
+	}
+
+	// the same through evmtest
+	if ok, _, err := v.TransactRaw(ctx, nil); ok || !errors.Is(err, expected) {
+		t.Errorf("TransactRaw: expected %v, got %v", expected, err)
+	}
+}
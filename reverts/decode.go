@@ -0,0 +1,229 @@
+package reverts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	// ErrNoRevertData is returned when a call reverted without data, e.g. when it
+	// ran out of gas.
+	ErrNoRevertData = errors.New("reverted without data")
+
+	// ErrUnknownRevert is returned when the revert data is neither a custom error of
+	// the verifier nor a reason string.
+	ErrUnknownRevert = errors.New("unknown revert data")
+)
+
+// VerifierError is a custom error of the verifier.
+type VerifierError struct {
+	Spec *Spec
+
+	// Args are the arguments of the error, in the order of Spec.Args
+	Args []*big.Int
+}
+
+func (e *VerifierError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Spec.Name)
+	sb.WriteByte('(')
+	for i, name := range e.Spec.Args {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%s=%s", name, e.Args[i])
+		if name == "offset" {
+			if word, ok := proofWordAt(e.Args[i]); ok {
+				fmt.Fprintf(&sb, " %s", word)
+			}
+		}
+	}
+	sb.WriteString("): ")
+	sb.WriteString(e.Unwrap().Error())
+	return sb.String()
+}
+
+// Unwrap returns the category of the error.
+func (e *VerifierError) Unwrap() error {
+	return e.Spec.category(e.Args)
+}
+
+// Is reports whether target is the same custom error with the same arguments, so
+// that errors.Is(err, reverts.New("PublicInputNotReduced", big.NewInt(0))) tests
+// both.
+func (e *VerifierError) Is(target error) bool {
+	t, ok := target.(*VerifierError)
+	if !ok || t.Spec.Name != e.Spec.Name || len(t.Args) != len(e.Args) {
+		return false
+	}
+	for i := range e.Args {
+		if e.Args[i].Cmp(t.Args[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Arg returns the argument of the error called name, nil if there is none.
+func (e *VerifierError) Arg(name string) *big.Int {
+	for i, n := range e.Spec.Args {
+		if n == name {
+			return e.Args[i]
+		}
+	}
+	return nil
+}
+
+// New returns the custom error called name with args. It panics if name is not in
+// Errors or if the number of arguments is wrong.
+func New(name string, args ...*big.Int) *VerifierError {
+	for i := range Errors {
+		if Errors[i].Name != name {
+			continue
+		}
+		if len(args) != len(Errors[i].Args) {
+			panic(fmt.Sprintf("%s expects %d arguments, got %d", name, len(Errors[i].Args), len(args)))
+		}
+		return &VerifierError{Spec: &Errors[i], Args: args}
+	}
+	panic("unknown error " + name)
+}
+
+// ReasonError is a revert with a reason string, e.g. from a require in TestVerifier.sol.
+type ReasonError struct {
+	Reason string
+}
+
+func (e *ReasonError) Error() string {
+	return "reverted: " + e.Reason
+}
+
+// selector of Error(string)
+var reasonSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// Decode decodes revert data. It returns a *VerifierError for a custom error of
+// the verifier, a *ReasonError for a reason string, ErrNoRevertData if data is
+// empty and ErrUnknownRevert otherwise.
+func Decode(data []byte) error {
+	if len(data) == 0 {
+		return ErrNoRevertData
+	}
+	if len(data) < 4 {
+		return fmt.Errorf("%w: %x", ErrUnknownRevert, data)
+	}
+
+	if bytes.Equal(data[:4], reasonSelector) {
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUnknownRevert, err)
+		}
+		return &ReasonError{Reason: reason}
+	}
+
+	for i := range Errors {
+		spec := &Errors[i]
+		selector := spec.Selector()
+		if !bytes.Equal(data[:4], selector[:]) {
+			continue
+		}
+		if len(data) != 4+32*len(spec.Args) {
+			return fmt.Errorf("%w: %s with %d bytes of arguments", ErrUnknownRevert, spec.Name, len(data)-4)
+		}
+		res := &VerifierError{Spec: spec, Args: make([]*big.Int, len(spec.Args))}
+		for j := range res.Args {
+			res.Args[j] = new(big.Int).SetBytes(data[4+32*j : 4+32*(j+1)])
+		}
+		return res
+	}
+
+	return fmt.Errorf("%w: selector %x", ErrUnknownRevert, data[:4])
+}
+
+// FromCallError decodes the revert data carried by err, the error returned by
+// eth_call or eth_estimateGas. If err has no revert data, it is returned as is.
+func FromCallError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var dataError interface{ ErrorData() interface{} }
+	if !errors.As(err, &dataError) {
+		return err
+	}
+	switch data := dataError.ErrorData().(type) {
+	case string:
+		b, decodeErr := hexutil.Decode(data)
+		if decodeErr != nil {
+			return err
+		}
+		return Decode(b)
+	case []byte:
+		return Decode(data)
+	default:
+		return err
+	}
+}
+
+// FromReceipt returns the reason why the transaction tx sent by from reverted. The
+// revert data is not part of the receipt, so tx is replayed as a call on top of
+// the block preceding the one of receipt. It returns nil if tx succeeded.
+//
+// The transactions mined before tx in its block are not replayed: if tx is not
+// the first of its block, and one of them changed a state it reads, the replay
+// may decode another revert than the one of tx, or none. Each transaction of
+// package evmtest is mined in its own block, so its revert is always the one of
+// the replay.
+//
+// If caller can't call on the parent block, e.g. go-ethereum's simulated backend
+// which only serves the latest block, tx is replayed on the latest block, on top
+// of the transactions mined after it.
+func FromReceipt(ctx context.Context, caller ethereum.ContractCaller, from common.Address, tx *types.Transaction, receipt *types.Receipt) error {
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return nil
+	}
+	msg := ethereum.CallMsg{
+		From:     from,
+		To:       tx.To(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}
+	parent := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	_, err := caller.CallContract(ctx, msg, parent)
+	var dataError interface{ ErrorData() interface{} }
+	if err != nil && !errors.As(err, &dataError) {
+		_, err = caller.CallContract(ctx, msg, nil)
+	}
+	if err != nil {
+		return FromCallError(err)
+	}
+	// the replay doesn't revert, the transaction most likely ran out of gas
+	return ErrNoRevertData
+}
+
+// proofWordAt returns the name of the word of the proof at offset, offset being
+// counted from the start of the bytes memory proof, as in Verifier.sol. The words
+// related to the BSB22 commitments are not named, their offsets depend on the
+// number of commitments.
+func proofWordAt(offset *big.Int) (string, bool) {
+	if !offset.IsInt64() {
+		return "", false
+	}
+	for _, w := range calldata.ProofWords(0) {
+		if int64(w.Offset+0x20) == offset.Int64() {
+			return w.Name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,159 @@
+package reverts
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestDecodeIs(t *testing.T) {
+
+	expected := New("WrongProofSize", big.NewInt(0x300), big.NewInt(0x320))
+	sel := expected.Spec.Selector()
+	data := append(sel[:], make([]byte, 64)...)
+	big.NewInt(0x300).FillBytes(data[4:36])
+	big.NewInt(0x320).FillBytes(data[36:68])
+
+	err := Decode(data)
+	if !errors.Is(err, expected) {
+		t.Fatalf("expected %v, got %v", expected, err)
+	}
+	if errors.Is(err, New("WrongProofSize", big.NewInt(0x2e0), big.NewInt(0x320))) {
+		t.Fatal("errors with different arguments match")
+	}
+	if errors.Is(err, New("WrongNumberOfPublicInputs", big.NewInt(0x300), big.NewInt(0x320))) {
+		t.Fatal("different errors with the same arguments match")
+	}
+	if !errors.Is(err, ErrMalformedCalldata) {
+		t.Fatalf("expected the category ErrMalformedCalldata, got %v", errors.Unwrap(err))
+	}
+
+	if err := Decode(nil); !errors.Is(err, ErrNoRevertData) {
+		t.Fatalf("expected ErrNoRevertData, got %v", err)
+	}
+	if err := Decode(sel[:]); !errors.Is(err, ErrUnknownRevert) {
+		t.Fatalf("expected ErrUnknownRevert for missing arguments, got %v", err)
+	}
+}
+
+// encode returns the revert data of the custom error spec with args
+func encode(spec *Spec, args ...*big.Int) []byte {
+	sel := spec.Selector()
+	res := append([]byte{}, sel[:]...)
+	for _, a := range args {
+		res = append(res, a.FillBytes(make([]byte, 32))...)
+	}
+	return res
+}
+
+func TestDecodeErrors(t *testing.T) {
+
+	// a size which is the one of a proof with 1 commitment, and one which is the
+	// size of no proof
+	wellFormedSize := big.NewInt(int64(calldata.ProofSize(1)))
+	malformedSize := big.NewInt(int64(calldata.ProofSize(1) + 1))
+	expected := big.NewInt(int64(calldata.ProofSize(0)))
+
+	testCases := []struct {
+		name     string
+		args     []*big.Int
+		category error
+	}{
+		{"WrongProofSize", []*big.Int{wellFormedSize, expected}, ErrVerifyingKeyMismatch},
+		{"WrongProofSize", []*big.Int{malformedSize, expected}, ErrMalformedCalldata},
+		{"WrongNumberOfPublicInputs", []*big.Int{big.NewInt(3), big.NewInt(2)}, ErrVerifyingKeyMismatch},
+		{"PublicInputNotReduced", []*big.Int{big.NewInt(1)}, ErrMalformedCalldata},
+		{"ProofScalarNotReduced", []*big.Int{big.NewInt(0x1c0)}, ErrMalformedCalldata},
+		{"ProofCoordinateNotReduced", []*big.Int{big.NewInt(0x20)}, ErrMalformedCalldata},
+		{"ProofPointNotOnCurve", []*big.Int{big.NewInt(0x60)}, ErrMalformedCalldata},
+		{"PrecompileFailed", []*big.Int{big.NewInt(8)}, ErrPrecompileFailed},
+		{"QuotientCheckFailed", nil, ErrInvalidProof},
+		{"PairingCheckFailed", nil, ErrInvalidProof},
+	}
+	categories := []error{ErrMalformedCalldata, ErrVerifyingKeyMismatch, ErrInvalidProof, ErrPrecompileFailed}
+
+	tested := make(map[string]bool)
+	for _, tc := range testCases {
+		tested[tc.name] = true
+		spec := New(tc.name, tc.args...).Spec
+
+		err := Decode(encode(spec, tc.args...))
+		var verifierError *VerifierError
+		if !errors.As(err, &verifierError) {
+			t.Errorf("%s: expected a *VerifierError, got %v", tc.name, err)
+			continue
+		}
+		if verifierError.Spec != spec {
+			t.Errorf("%s: decoded as %s", tc.name, verifierError.Spec.Name)
+		}
+		if !errors.Is(err, New(tc.name, tc.args...)) {
+			t.Errorf("%s: decoded arguments %v, expected %v", tc.name, verifierError.Args, tc.args)
+		}
+		for i, name := range spec.Args {
+			if arg := verifierError.Arg(name); arg == nil || arg.Cmp(tc.args[i]) != 0 {
+				t.Errorf("%s: Arg(%q) = %v, expected %v", tc.name, name, arg, tc.args[i])
+			}
+		}
+		if verifierError.Arg("unknown") != nil {
+			t.Errorf("%s: Arg of an unknown argument is not nil", tc.name)
+		}
+		for _, c := range categories {
+			if errors.Is(err, c) != (c == tc.category) {
+				t.Errorf("%s%v: errors.Is(err, %v) = %v", tc.name, tc.args, c, errors.Is(err, c))
+			}
+		}
+
+		// an argument too many
+		extra := append(encode(spec, tc.args...), make([]byte, 32)...)
+		if err := Decode(extra); !errors.Is(err, ErrUnknownRevert) {
+			t.Errorf("%s with an extra argument: expected ErrUnknownRevert, got %v", tc.name, err)
+		}
+	}
+	for i := range Errors {
+		if !tested[Errors[i].Name] {
+			t.Errorf("%s is not tested", Errors[i].Name)
+		}
+	}
+
+	// a proof word is named after its offset
+	if err := Decode(encode(New("ProofCoordinateNotReduced", big.NewInt(0x20)).Spec, big.NewInt(0x20))); !strings.Contains(err.Error(), "proof_l_com_x") {
+		t.Errorf("the word at 0x20 is not named: %v", err)
+	}
+}
+
+func TestDecodeReason(t *testing.T) {
+
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := abi.Arguments{{Type: stringType}}.Pack("verification failed!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := append(crypto.Keccak256([]byte("Error(string)"))[:4], args...)
+
+	err = Decode(data)
+	var reasonError *ReasonError
+	if !errors.As(err, &reasonError) || reasonError.Reason != "verification failed!" {
+		t.Fatalf("expected the reason string, got %v", err)
+	}
+	for _, c := range []error{ErrMalformedCalldata, ErrVerifyingKeyMismatch, ErrInvalidProof, ErrPrecompileFailed} {
+		if errors.Is(err, c) {
+			t.Errorf("a reason string is in the category %v", c)
+		}
+	}
+
+	// a reason cut short of its string
+	if err := Decode(data[:len(data)-32]); !errors.Is(err, ErrUnknownRevert) {
+		t.Errorf("truncated reason: expected ErrUnknownRevert, got %v", err)
+	}
+	if err := Decode([]byte{0x12, 0x34, 0x56, 0x78}); !errors.Is(err, ErrUnknownRevert) {
+		t.Errorf("unknown selector: expected ErrUnknownRevert, got %v", err)
+	}
+}
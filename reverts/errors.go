@@ -0,0 +1,155 @@
+// Package reverts decodes the custom errors of the generated verifier.
+//
+// Verify reverts with one of the custom errors listed in Errors when the
+// verification fails. Each error falls in a category telling apart a malformed
+// call, a call made with a verifying key other than the one of the verifier, an
+// invalid proof and a failure of the EVM.
+package reverts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Categories of the custom errors, use errors.Is to test the category of an error
+// returned by Decode.
+var (
+	// ErrMalformedCalldata is the category of the errors caused by arguments which
+	// are not a valid encoding of a proof or of public inputs.
+	ErrMalformedCalldata = errors.New("malformed calldata")
+
+	// ErrVerifyingKeyMismatch is the category of the errors caused by a proof or
+	// public inputs whose shape is valid for another verifying key.
+	ErrVerifyingKeyMismatch = errors.New("verifying key mismatch")
+
+	// ErrInvalidProof is the category of the errors caused by a well formed proof
+	// which doesn't verify.
+	ErrInvalidProof = errors.New("invalid proof")
+
+	// ErrPrecompileFailed is the category of the errors caused by a call to a
+	// precompile which failed, usually because it ran out of gas.
+	ErrPrecompileFailed = errors.New("precompile failed")
+)
+
+// Spec describes a custom error of the verifier. Every argument is a uint256.
+type Spec struct {
+
+	// Name of the error in Solidity, e.g. WrongProofSize
+	Name string
+
+	// Args are the names of the arguments of the error
+	Args []string
+
+	// Comment is emitted above the declaration of the error in Verifier.sol
+	Comment string
+
+	// category returns the category of the error from its arguments
+	category func(args []*big.Int) error
+}
+
+// Errors lists the custom errors of the verifier.
+var Errors = []Spec{
+	{
+		Name:    "WrongProofSize",
+		Args:    []string{"size", "expected"},
+		Comment: "the size of the proof doesn't match the number of BSB22 commitments of the vk",
+		category: func(args []*big.Int) error {
+			// the proof is well formed for another number of commitments
+			if args[0].IsInt64() {
+				if _, err := calldata.NbCommitments(int(args[0].Int64())); err == nil {
+					return ErrVerifyingKeyMismatch
+				}
+			}
+			return ErrMalformedCalldata
+		},
+	},
+	{
+		Name:     "WrongNumberOfPublicInputs",
+		Args:     []string{"nb", "expected"},
+		Comment:  "the number of public inputs doesn't match the vk",
+		category: func([]*big.Int) error { return ErrVerifyingKeyMismatch },
+	},
+	{
+		Name:     "PublicInputNotReduced",
+		Args:     []string{"index"},
+		Comment:  "a public input is not reduced mod r",
+		category: func([]*big.Int) error { return ErrMalformedCalldata },
+	},
+	{
+		Name:     "ProofScalarNotReduced",
+		Args:     []string{"offset"},
+		Comment:  "the scalar at offset in the proof is not reduced mod r",
+		category: func([]*big.Int) error { return ErrMalformedCalldata },
+	},
+	{
+		Name:     "ProofCoordinateNotReduced",
+		Args:     []string{"offset"},
+		Comment:  "a coordinate of the point at offset in the proof is not reduced mod p",
+		category: func([]*big.Int) error { return ErrMalformedCalldata },
+	},
+	{
+		Name:     "ProofPointNotOnCurve",
+		Args:     []string{"offset"},
+		Comment:  "the point at offset in the proof is neither on Bn254 nor (0,0)",
+		category: func([]*big.Int) error { return ErrMalformedCalldata },
+	},
+	{
+		Name:     "PrecompileFailed",
+		Args:     []string{"precompile"},
+		Comment:  "the call to the precompile at this address failed",
+		category: func([]*big.Int) error { return ErrPrecompileFailed },
+	},
+	{
+		Name:     "QuotientCheckFailed",
+		Comment:  "the claimed quotient doesn't match the claimed evaluations at zeta",
+		category: func([]*big.Int) error { return ErrInvalidProof },
+	},
+	{
+		Name:     "PairingCheckFailed",
+		Comment:  "the batched KZG opening proofs don't verify",
+		category: func([]*big.Int) error { return ErrInvalidProof },
+	},
+}
+
+// Signature returns the signature of the error, e.g. WrongProofSize(uint256,uint256)
+func (s *Spec) Signature() string {
+	args := make([]string, len(s.Args))
+	for i := range args {
+		args[i] = "uint256"
+	}
+	return s.Name + "(" + strings.Join(args, ",") + ")"
+}
+
+// Selector returns the 4 bytes selector of the error.
+func (s *Spec) Selector() [4]byte {
+	var res [4]byte
+	copy(res[:], crypto.Keccak256([]byte(s.Signature())))
+	return res
+}
+
+// Constant returns the name of the Solidity constant holding the selector, left
+// aligned on 32 bytes so that it can be stored with a single mstore, e.g.
+// error_wrong_proof_size.
+func (s *Spec) Constant() string {
+	var sb strings.Builder
+	sb.WriteString("error")
+	for _, r := range s.Name {
+		if unicode.IsUpper(r) {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// SelectorWord returns the selector left aligned on 32 bytes, as a hex string.
+func (s *Spec) SelectorWord() string {
+	sel := s.Selector()
+	return fmt.Sprintf("0x%x%056x", sel[:], 0)
+}
@@ -0,0 +1,50 @@
+package shadow
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/plonk-solidity/debug"
+)
+
+// Divergence is the first difference between two runs of Verify.
+type Divergence struct {
+	Phase debug.Phase
+
+	// Slot is the first slot whose value differs at the end of Phase, nil if the
+	// runs don't reach the same phases
+	Slot *debug.Slot
+
+	Expected, Actual *big.Int
+}
+
+func (d *Divergence) String() string {
+	if d.Slot == nil {
+		return fmt.Sprintf("the runs diverge before the end of %s", d.Phase)
+	}
+	return fmt.Sprintf("after %s, state_%s is %#x, expected %#x", d.Phase, d.Slot.Name, d.Actual, d.Expected)
+}
+
+// Diff compares two runs of Verify phase by phase, typically the states returned
+// by Verify (expected) and the ones logged by a verifier generated in debug mode
+// (actual). It returns the first divergence, nil if there is none. The slots
+// whose value is unknown in either run are skipped.
+func Diff(expected, actual []debug.State) *Divergence {
+	layout := debug.StateLayout()
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		if i >= len(expected) {
+			return &Divergence{Phase: actual[i].Phase}
+		}
+		if i >= len(actual) || actual[i].Phase != expected[i].Phase {
+			return &Divergence{Phase: expected[i].Phase}
+		}
+		for j := range layout {
+			e, a := expected[i].Values[j], actual[i].Values[j]
+			if e == nil || a == nil || e.Cmp(a) == 0 {
+				continue
+			}
+			return &Divergence{Phase: expected[i].Phase, Slot: &layout[j], Expected: e, Actual: a}
+		}
+	}
+	return nil
+}
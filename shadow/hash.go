@@ -0,0 +1,57 @@
+package shadow
+
+import (
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// dst is the domain separation tag of hash_fr in Utils.sol
+const dst = "BSB22-Plonk"
+
+// hashFr hashes the commitment x||y to Fr as hash_fr in Utils.sol: the 48 bytes
+// output by expandMsg are read in big endian and reduced mod r.
+func hashFr(x, y []byte) fr.Element {
+	msg := expandMsg(x, y)
+	var res fr.Element
+	res.SetBigInt(new(big.Int).SetBytes(msg[:]))
+	return res
+}
+
+// expandMsg expands x||y to 48 bytes as expand_msg in Utils.sol, see
+// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06#section-5
+func expandMsg(x, y []byte) (res [48]byte) {
+
+	// b₀ = sha256(pad || x || y || 0 || 48 || 0 || dst || len(dst)), pad being 64 zeros
+	h := sha256.New()
+	h.Write(make([]byte, 64))
+	h.Write(x)
+	h.Write(y)
+	h.Write([]byte{0, 48, 0})
+	h.Write([]byte(dst))
+	h.Write([]byte{byte(len(dst))})
+	b0 := h.Sum(nil)
+
+	// b₁ = sha256(b₀ || 1 || dst || len(dst))
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write([]byte(dst))
+	h.Write([]byte{byte(len(dst))})
+	b1 := h.Sum(nil)
+	copy(res[:32], b1)
+
+	// b₂ = sha256(b₀ ⊕ b₁ || 2 || dst || len(dst))
+	h.Reset()
+	for i := range b0 {
+		h.Write([]byte{b0[i] ^ b1[i]})
+	}
+	h.Write([]byte{2})
+	h.Write([]byte(dst))
+	h.Write([]byte{byte(len(dst))})
+	b2 := h.Sum(nil)
+	copy(res[32:], b2[:16])
+
+	return
+}
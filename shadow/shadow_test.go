@@ -0,0 +1,132 @@
+package shadow
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/debug"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/reverts"
+)
+
+// call is a call to Verify, with the public inputs as gnark takes them
+type call struct {
+	name  string
+	proof []byte
+	pi    fr.Vector
+}
+
+// wrongCalls returns the calls with a wrong public input x+1 in place of x, and
+// with a wrong claimed value t(ζ)+1
+func wrongCalls(t *testing.T, proof []byte, pi fr.Vector, nbCommitments int) []call {
+	t.Helper()
+
+	var res []call
+	one := new(fr.Element).SetOne()
+	if len(pi) != 0 {
+		wrong := append(fr.Vector{}, pi...)
+		wrong[0].Add(&wrong[0], one)
+		res = append(res, call{"wrong public input", proof, wrong})
+	}
+
+	for _, f := range calldata.ProofLayout(nbCommitments) {
+		if f.Name != "quotient_polynomial_at_zeta" {
+			continue
+		}
+		var v fr.Element
+		v.SetBytes(proof[f.Offset : f.Offset+fr.Bytes])
+		v.Add(&v, one)
+		b := v.Bytes()
+		wrong := append([]byte{}, proof...)
+		copy(wrong[f.Offset:], b[:])
+		return append(res, call{"wrong quotient_polynomial_at_zeta", wrong, pi})
+	}
+	t.Fatal("no quotient_polynomial_at_zeta in the proof layout")
+	return nil
+}
+
+func bigInts(pi fr.Vector) []*big.Int {
+	res := make([]*big.Int, len(pi))
+	for i := range pi {
+		res[i] = new(big.Int)
+		pi[i].BigInt(res[i])
+	}
+	return res
+}
+
+func TestVerify(t *testing.T) {
+
+	for _, example := range circuits.Examples {
+		example := example
+		t.Run(example.Name, func(t *testing.T) {
+			setup, err := example.Setup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, pi, err := setup.Prove(example.Assignment())
+			if err != nil {
+				t.Fatal(err)
+			}
+			data := calldata.MarshalProof(*proof)
+
+			// the valid proof is accepted, every phase is logged
+			states, err := Verify(setup.VK, data, bigInts(pi))
+			if err != nil {
+				t.Fatalf("the valid proof is rejected: %v", err)
+			}
+			if len(states) != len(debug.Phases()) {
+				t.Fatalf("%d states, expected %d", len(states), len(debug.Phases()))
+			}
+			for i, s := range states {
+				if s.Phase != debug.Phases()[i] {
+					t.Errorf("state %d is logged after %s, expected %s", i, s.Phase, debug.Phases()[i])
+				}
+			}
+
+			// the wrong calls are rejected as invalid proofs
+			for _, c := range wrongCalls(t, data, pi, len(proof.Bsb22Commitments)) {
+				_, err := Verify(setup.VK, c.proof, bigInts(c.pi))
+				if !errors.Is(err, reverts.ErrInvalidProof) {
+					t.Errorf("%s: expected an invalid proof, got %v", c.name, err)
+				}
+			}
+		})
+	}
+}
+
+// TestVerifyAgreesWithGnark checks that Verify accepts the calls gnark's verifier
+// accepts, and only those
+func TestVerifyAgreesWithGnark(t *testing.T) {
+
+	for _, example := range circuits.Examples {
+		example := example
+		t.Run(example.Name, func(t *testing.T) {
+			setup, err := example.Setup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, pi, err := setup.Prove(example.Assignment())
+			if err != nil {
+				t.Fatal(err)
+			}
+			data := calldata.MarshalProof(*proof)
+
+			calls := append([]call{{"valid proof", data, pi}}, wrongCalls(t, data, pi, len(proof.Bsb22Commitments))...)
+			for _, c := range calls {
+				p, err := calldata.UnmarshalProof(c.proof)
+				if err != nil {
+					t.Fatalf("%s: %v", c.name, err)
+				}
+				gnarkErr := bn254plonk.Verify(&p, setup.VK, c.pi)
+				_, shadowErr := Verify(setup.VK, c.proof, bigInts(c.pi))
+				if (gnarkErr == nil) != (shadowErr == nil) {
+					t.Errorf("%s: gnark returns %v, shadow %v", c.name, gnarkErr, shadowErr)
+				}
+			}
+		})
+	}
+}
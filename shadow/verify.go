@@ -0,0 +1,686 @@
+// Package shadow is a Go implementation of Verify in Verifier.sol, which follows
+// the Solidity code step by step and computes every state_* slot.
+//
+// When gnark accepts a proof that the contract rejects, diffing the states logged
+// by a verifier generated in debug mode (see package debug) against the states
+// computed here tells which step of Verifier.sol diverged. The code mirrors
+// Verifier.sol rather than the PLONK verifier of gnark, quirks included: the
+// challenges are hashed before being reduced, the coset shift is hardcoded, the
+// batch inversion zeroes everything if an element is zero, etc.
+package shadow
+
+import (
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/debug"
+	"github.com/consensys/plonk-solidity/reverts"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// cosetShift is vk_coset_shift, which is hardcoded in Verifier.sol
+const cosetShift = 5
+
+var rMinusTwo = new(big.Int).Sub(fr.Modulus(), big.NewInt(2))
+
+// slots maps the name of a slot to its index in debug.StateLayout
+var slots = func() map[string]int {
+	res := make(map[string]int)
+	for i, s := range debug.StateLayout() {
+		res[s.Name] = i
+	}
+	return res
+}()
+
+// Verify runs Verify of the verifier generated for vk on proof and publicInputs,
+// the arguments of Verify in Verifier.sol. It returns the states logged after each
+// phase by a verifier generated in debug mode. If the verification fails, err is
+// the *reverts.VerifierError the verifier reverts with, and states are the ones
+// of the phases which completed.
+//
+// The slots Verify never writes (state_sv, state_su and
+// state_alpha_square_lagrange_one) hold whatever was in memory before, their
+// values are nil.
+func Verify(vk *bn254plonk.VerifyingKey, proof []byte, publicInputs []*big.Int) (states []debug.State, err error) {
+
+	v := verifier{
+		vk:           vk,
+		proof:        proof,
+		publicInputs: publicInputs,
+		fields:       calldata.ProofLayout(len(vk.CommitmentConstraintIndexes)),
+		state:        make([]*big.Int, len(slots)),
+	}
+
+	if err := v.checkInputs(); err != nil {
+		return nil, err
+	}
+
+	gamma, beta, alpha, zeta := v.deriveGammaBetaAlphaZeta()
+	pi := v.computePi(&zeta)
+
+	v.setFr("alpha", &alpha)
+	v.setFr("gamma", &gamma)
+	v.setFr("zeta", &zeta)
+	v.setFr("beta", &beta)
+	v.setFr("pi", &pi)
+	v.log(debug.PhaseChallenges)
+
+	steps := []struct {
+		phase debug.Phase
+		run   func() error
+	}{
+		{debug.PhaseAlphaSquareLagrange, v.computeAlphaSquareLagrange0},
+		{debug.PhaseQuotientEval, v.verifyQuotientPolyEvalAtZeta},
+		{debug.PhaseFoldH, v.foldH},
+		{debug.PhaseLinearisedPolynomial, v.computeCommitmentLinearisedPolynomial},
+		{debug.PhaseGammaKzg, v.computeGammaKzg},
+		{debug.PhaseFoldState, v.foldState},
+		{debug.PhaseBatchVerify, v.batchVerifyMultiPoints},
+	}
+	for _, s := range steps {
+		if err := s.run(); err != nil {
+			return v.states, err
+		}
+		v.log(s.phase)
+	}
+
+	return v.states, nil
+}
+
+type verifier struct {
+	vk           *bn254plonk.VerifyingKey
+	proof        []byte
+	publicInputs []*big.Int
+	fields       []calldata.Field
+
+	// state is the memory addressed by the state_* constants, indexed as
+	// debug.StateLayout, a slot is nil until it is written
+	state  []*big.Int
+	states []debug.State
+
+	// digests and claimed values hashed by compute_gamma_kzg, fold_state reads
+	// them back from the memory following the state
+	kzgDigests       []bn254.G1Affine
+	kzgClaimedValues []fr.Element
+}
+
+// log appends a copy of the state to the states logged so far
+func (v *verifier) log(phase debug.Phase) {
+	values := make([]*big.Int, len(v.state))
+	for i, x := range v.state {
+		if x != nil {
+			values[i] = new(big.Int).Set(x)
+		}
+	}
+	v.states = append(v.states, debug.State{Phase: phase, Values: values})
+}
+
+// -------- state
+
+func (v *verifier) getFr(name string) fr.Element {
+	var res fr.Element
+	res.SetBigInt(v.state[slots[name]])
+	return res
+}
+
+func (v *verifier) setFr(name string, x *fr.Element) {
+	v.state[slots[name]] = x.BigInt(new(big.Int))
+}
+
+// getPoint reads the point stored at state_<name>_x, state_<name>_y
+func (v *verifier) getPoint(name string) bn254.G1Affine {
+	var res bn254.G1Affine
+	res.X.SetBigInt(v.state[slots[name+"_x"]])
+	res.Y.SetBigInt(v.state[slots[name+"_y"]])
+	return res
+}
+
+func (v *verifier) setPoint(name string, p *bn254.G1Affine) {
+	v.state[slots[name+"_x"]] = p.X.BigInt(new(big.Int))
+	v.state[slots[name+"_y"]] = p.Y.BigInt(new(big.Int))
+}
+
+// -------- proof
+
+// field returns the field of the proof called name, index is the index of the
+// BSB22 commitment the field relates to, -1 for the other fields.
+func (v *verifier) field(name string, index int) *calldata.Field {
+	for i := range v.fields {
+		if v.fields[i].Name == name && v.fields[i].Index == index {
+			return &v.fields[i]
+		}
+	}
+	panic("no field " + name + " in the proof")
+}
+
+// bytes returns the serialised field f
+func (v *verifier) bytes(f *calldata.Field) []byte {
+	return v.proof[f.Offset : f.Offset+f.Size()]
+}
+
+func (v *verifier) word(offset int) *big.Int {
+	return new(big.Int).SetBytes(v.proof[offset : offset+fr.Bytes])
+}
+
+func (v *verifier) scalarAt(f *calldata.Field) fr.Element {
+	var res fr.Element
+	res.SetBigInt(v.word(f.Offset))
+	return res
+}
+
+func (v *verifier) pointAt(f *calldata.Field) bn254.G1Affine {
+	var res bn254.G1Affine
+	res.X.SetBigInt(v.word(f.Offset))
+	res.Y.SetBigInt(v.word(f.Offset + fp.Bytes))
+	return res
+}
+
+func (v *verifier) scalar(name string) fr.Element {
+	return v.scalarAt(v.field(name, -1))
+}
+
+func (v *verifier) point(name string) bn254.G1Affine {
+	return v.pointAt(v.field(name, -1))
+}
+
+// -------- check_inputs
+
+func (v *verifier) checkInputs() error {
+	expected := calldata.ProofSize(len(v.vk.CommitmentConstraintIndexes))
+	if len(v.proof) != expected {
+		return reverts.New("WrongProofSize", big.NewInt(int64(len(v.proof))), big.NewInt(int64(expected)))
+	}
+	if uint64(len(v.publicInputs)) != v.vk.NbPublicVariables {
+		return reverts.New("WrongNumberOfPublicInputs", big.NewInt(int64(len(v.publicInputs))), new(big.Int).SetUint64(v.vk.NbPublicVariables))
+	}
+	for i, x := range v.publicInputs {
+		if x.Cmp(fr.Modulus()) >= 0 {
+			return reverts.New("PublicInputNotReduced", big.NewInt(int64(i)))
+		}
+	}
+	return v.checkProof()
+}
+
+func (v *verifier) checkProof() error {
+	for i := range v.fields {
+		f := &v.fields[i]
+
+		// offset in the bytes memory proof, whose first word is the size of the proof
+		offset := big.NewInt(int64(f.Offset + 0x20))
+
+		if f.Kind == calldata.Scalar {
+			if v.word(f.Offset).Cmp(fr.Modulus()) >= 0 {
+				return reverts.New("ProofScalarNotReduced", offset)
+			}
+			continue
+		}
+
+		x, y := v.word(f.Offset), v.word(f.Offset+fp.Bytes)
+		if x.Cmp(fp.Modulus()) >= 0 || y.Cmp(fp.Modulus()) >= 0 {
+			return reverts.New("ProofCoordinateNotReduced", offset)
+		}
+		if x.Sign() == 0 && y.Sign() == 0 {
+			continue
+		}
+		p := v.pointAt(f)
+		if !p.IsOnCurve() {
+			return reverts.New("ProofPointNotOnCurve", offset)
+		}
+	}
+	return nil
+}
+
+// -------- derive_gamma_beta_alpha_zeta
+
+// deriveGammaBetaAlphaZeta computes the challenges with Fiat Shamir. Each
+// challenge is hashed with the previous one as output by sha256, that is before
+// it is reduced mod r.
+func (v *verifier) deriveGammaBetaAlphaZeta() (gamma, beta, alpha, zeta fr.Element) {
+	vk := v.vk
+
+	transcript := []byte("gamma")
+	for i := range vk.S {
+		transcript = appendPoint(transcript, &vk.S[i])
+	}
+	for _, q := range []*bn254.G1Affine{&vk.Ql, &vk.Qr, &vk.Qm, &vk.Qo, &vk.Qk} {
+		transcript = appendPoint(transcript, q)
+	}
+	for _, x := range v.publicInputs {
+		transcript = append(transcript, x.FillBytes(make([]byte, fr.Bytes))...)
+	}
+	for i := range vk.CommitmentConstraintIndexes {
+		transcript = append(transcript, v.bytes(v.field("commitments_wires_commit_api", i))...)
+	}
+	for _, name := range []string{"l_com", "r_com", "o_com"} {
+		transcript = append(transcript, v.bytes(v.field(name, -1))...)
+	}
+	gammaNotReduced := sha256.Sum256(transcript)
+
+	transcript = append([]byte("beta"), gammaNotReduced[:]...)
+	betaNotReduced := sha256.Sum256(transcript)
+
+	transcript = append([]byte("alpha"), betaNotReduced[:]...)
+	transcript = append(transcript, v.bytes(v.field("grand_product_commitment", -1))...)
+	alphaNotReduced := sha256.Sum256(transcript)
+
+	transcript = append([]byte("zeta"), alphaNotReduced[:]...)
+	for _, name := range []string{"h_0", "h_1", "h_2"} {
+		transcript = append(transcript, v.bytes(v.field(name, -1))...)
+	}
+	zetaNotReduced := sha256.Sum256(transcript)
+
+	gamma.SetBytes(gammaNotReduced[:])
+	beta.SetBytes(betaNotReduced[:])
+	alpha.SetBytes(alphaNotReduced[:])
+	zeta.SetBytes(zetaNotReduced[:])
+	return
+}
+
+// -------- compute_pi
+
+// computePi computes PI(ζ) = ∑ᵢ Lᵢ(ζ)xᵢ, where the xᵢ are the public inputs followed
+// by the hashes of the BSB22 commitments.
+func (v *verifier) computePi(zeta *fr.Element) fr.Element {
+
+	var pi, x fr.Element
+	lagranges := v.batchComputeLagrangesAtZ(zeta, len(v.publicInputs))
+	for i := range v.publicInputs {
+		x.SetBigInt(v.publicInputs[i])
+		x.Mul(&x, &lagranges[i])
+		pi.Add(&pi, &x)
+	}
+
+	for i, index := range v.vk.CommitmentConstraintIndexes {
+		commitment := v.bytes(v.field("commitments_wires_commit_api", i))
+		hash := hashFr(commitment[:fp.Bytes], commitment[fp.Bytes:])
+		a := v.computeIthLagrangeAtZ(zeta, new(big.Int).SetUint64(index+uint64(len(v.publicInputs))))
+		a.Mul(&a, &hash)
+		pi.Add(&pi, &a)
+	}
+
+	return pi
+}
+
+// batchComputeLagrangesAtZ returns [L₀(ζ), .., L_{n-1}(ζ)], where
+// Lᵢ(ζ) = ωⁱ/N * (ζᴺ-1)/(ζ-ωⁱ) and N is the size of the domain.
+func (v *verifier) batchComputeLagrangesAtZ(z *fr.Element, n int) []fr.Element {
+	var one fr.Element
+	one.SetOne()
+
+	zn := pow(z, new(big.Int).SetUint64(v.vk.Size))
+	zn.Sub(&zn, &one)
+	zn.Mul(&zn, &v.vk.SizeInv)
+
+	res := make([]fr.Element, n)
+	w := one
+	for i := range res {
+		res[i].Sub(z, &w)
+		w.Mul(&w, &v.vk.Generator)
+	}
+	batchInvert(res)
+	w.SetOne()
+	for i := range res {
+		res[i].Mul(&res[i], &zn).Mul(&res[i], &w)
+		w.Mul(&w, &v.vk.Generator)
+	}
+	return res
+}
+
+// computeIthLagrangeAtZ returns Lᵢ(ζ) = ωⁱ/N * (ζᴺ-1)/(ζ-ωⁱ)
+func (v *verifier) computeIthLagrangeAtZ(zeta *fr.Element, i *big.Int) fr.Element {
+	var one fr.Element
+	one.SetOne()
+
+	w := pow(&v.vk.Generator, i)
+	var den fr.Element
+	den.Sub(zeta, &w)
+	zn := pow(zeta, new(big.Int).SetUint64(v.vk.Size))
+	zn.Sub(&zn, &one)
+	w.Mul(&w, &v.vk.SizeInv)
+	den = pow(&den, rMinusTwo)
+	w.Mul(&w, &den)
+	w.Mul(&w, &zn)
+	return w
+}
+
+// batchInvert inverts a in place as batch_invert in Verifier.sol does: the
+// product of the elements is inverted, so if one of them is zero every element is
+// set to zero.
+func batchInvert(a []fr.Element) {
+	prefix := make([]fr.Element, len(a)+1)
+	prefix[0].SetOne()
+	for i := range a {
+		prefix[i+1].Mul(&prefix[i], &a[i])
+	}
+	inv := pow(&prefix[len(a)], rMinusTwo)
+	for i := len(a) - 1; i >= 0; i-- {
+		tmp := a[i]
+		a[i].Mul(&inv, &prefix[i])
+		inv.Mul(&inv, &tmp)
+	}
+}
+
+// -------- compute_alpha_square_lagrange_0
+
+func (v *verifier) computeAlphaSquareLagrange0() error {
+	var one fr.Element
+	one.SetOne()
+	zeta := v.getFr("zeta")
+	alpha := v.getFr("alpha")
+
+	res := pow(&zeta, new(big.Int).SetUint64(v.vk.Size))
+	res.Sub(&res, &one)
+	v.setFr("zeta_power_n_minus_one", &res)
+
+	var den fr.Element
+	den.Sub(&zeta, &one)
+	den = pow(&den, rMinusTwo)
+	den.Mul(&den, &v.vk.SizeInv)
+	res.Mul(&res, &den).Mul(&res, &alpha).Mul(&res, &alpha)
+	v.setFr("alpha_square_lagrange", &res)
+
+	return nil
+}
+
+// -------- verify_quotient_poly_eval_at_zeta
+
+func (v *verifier) verifyQuotientPolyEvalAtZeta() error {
+	beta := v.getFr("beta")
+	gamma := v.getFr("gamma")
+	alpha := v.getFr("alpha")
+
+	// (l(ζ)+β*s1(ζ)+γ)
+	s1 := v.scalar("s1_at_zeta")
+	l := v.scalar("l_at_zeta")
+	s1.Mul(&s1, &beta).Add(&s1, &gamma).Add(&s1, &l)
+
+	// (r(ζ)+β*s2(ζ)+γ)
+	s2 := v.scalar("s2_at_zeta")
+	r := v.scalar("r_at_zeta")
+	s2.Mul(&s2, &beta).Add(&s2, &gamma).Add(&s2, &r)
+
+	// (o(ζ)+γ)
+	o := v.scalar("o_at_zeta")
+	o.Add(&o, &gamma)
+
+	//  α*(Z(μζ))*(l(ζ)+β*s1(ζ)+γ)*(r(ζ)+β*s2(ζ)+γ)*(o(ζ)+γ)
+	zOmega := v.scalar("grand_product_at_zeta_omega")
+	s1.Mul(&s1, &s2).Mul(&s1, &o).Mul(&s1, &alpha).Mul(&s1, &zOmega)
+
+	// linearizedpolynomial + pi(zeta) + s1 - α²L₁(ζ)
+	computedQuotient := v.scalar("linearised_polynomial_at_zeta")
+	pi := v.getFr("pi")
+	alphaSquareLagrange := v.getFr("alpha_square_lagrange")
+	computedQuotient.Add(&computedQuotient, &pi).Add(&computedQuotient, &s1).Sub(&computedQuotient, &alphaSquareLagrange)
+
+	// t(ζ)(ζⁿ-1)
+	quotient := v.scalar("quotient_polynomial_at_zeta")
+	zetaPowerNMinusOne := v.getFr("zeta_power_n_minus_one")
+	quotient.Mul(&quotient, &zetaPowerNMinusOne)
+
+	if !computedQuotient.Equal(&quotient) {
+		return reverts.New("QuotientCheckFailed")
+	}
+	return nil
+}
+
+// -------- fold_h
+
+// foldH computes H₁ + ζᵐ⁺²*H₂ + ζ²⁽ᵐ⁺²⁾*H₃
+func (v *verifier) foldH() error {
+	zeta := v.getFr("zeta")
+	zetaPowerNPlusTwo := pow(&zeta, new(big.Int).SetUint64(v.vk.Size+2))
+
+	h0, h1, h2 := v.point("h_0"), v.point("h_1"), v.point("h_2")
+	folded := ecMul(&h2, &zetaPowerNPlusTwo)
+	folded.Add(&folded, &h1)
+	folded = ecMul(&folded, &zetaPowerNPlusTwo)
+	folded.Add(&folded, &h0)
+	v.setPoint("folded_h", &folded)
+
+	return nil
+}
+
+// -------- compute_commitment_linearised_polynomial
+
+func (v *verifier) computeCommitmentLinearisedPolynomial() error {
+	beta := v.getFr("beta")
+	gamma := v.getFr("gamma")
+	zeta := v.getFr("zeta")
+	alpha := v.getFr("alpha")
+
+	l, r, o := v.scalar("l_at_zeta"), v.scalar("r_at_zeta"), v.scalar("o_at_zeta")
+	s1AtZeta, s2AtZeta := v.scalar("s1_at_zeta"), v.scalar("s2_at_zeta")
+	zOmega := v.scalar("grand_product_at_zeta_omega")
+
+	var u, w, x, s1, s2 fr.Element
+	u.Mul(&zOmega, &beta)
+	w.Mul(&beta, &s1AtZeta).Add(&w, &l).Add(&w, &gamma)
+	x.Mul(&beta, &s2AtZeta).Add(&x, &r).Add(&x, &gamma)
+	s1.Mul(&u, &w).Mul(&s1, &x).Mul(&s1, &alpha)
+
+	var shift, shiftSquare, betaZeta fr.Element
+	shift.SetUint64(cosetShift)
+	shiftSquare.Square(&shift)
+	betaZeta.Mul(&beta, &zeta)
+	u.Add(&betaZeta, &l).Add(&u, &gamma)
+	w.Mul(&betaZeta, &shift).Add(&w, &r).Add(&w, &gamma)
+	x.Mul(&betaZeta, &shiftSquare).Add(&x, &o).Add(&x, &gamma)
+	alphaSquareLagrange := v.getFr("alpha_square_lagrange")
+	s2.Mul(&u, &w).Mul(&s2, &x).Neg(&s2).Mul(&s2, &alpha).Add(&s2, &alphaSquareLagrange)
+
+	// at this stage:
+	// * s₁ = α*Z(μζ)(l(ζ)+β*s₁(ζ)+γ)*(r(ζ)+β*s₂(ζ)+γ)*β
+	// * s₂ = -α*(l(ζ)+β*ζ+γ)*(r(ζ)+β*u*ζ+γ)*(o(ζ)+β*u²*ζ+γ) + α²*L₁(ζ)
+	v.computeCommitmentLinearisedPolynomialEc(&s1, &s2)
+
+	return nil
+}
+
+func (v *verifier) computeCommitmentLinearisedPolynomialEc(s1, s2 *fr.Element) {
+	vk := v.vk
+	l, r, o := v.scalar("l_at_zeta"), v.scalar("r_at_zeta"), v.scalar("o_at_zeta")
+
+	var rl fr.Element
+	rl.Mul(&l, &r)
+
+	res := ecMul(&vk.Ql, &l)
+	accMul(&res, &vk.Qr, &r)
+	accMul(&res, &vk.Qm, &rl)
+	accMul(&res, &vk.Qo, &o)
+	res.Add(&res, &vk.Qk)
+
+	for i := range vk.CommitmentConstraintIndexes {
+		commitment := v.pointAt(v.field("commitments_wires_commit_api", i))
+		opening := v.scalarAt(v.field("openings_selector_commit_api_at_zeta", i))
+		accMul(&res, &commitment, &opening)
+	}
+
+	accMul(&res, &vk.S[2], s1)
+	z := v.point("grand_product_commitment")
+	accMul(&res, &z, s2)
+
+	v.setPoint("linearised_polynomial", &res)
+}
+
+// -------- compute_gamma_kzg
+
+// computeGammaKzg derives the challenge folding the openings at ζ, from ζ, the
+// digests [H], [Linearised polynomial], [L], [R], [O], [S₁], [S₂], [Qcpᵢ] and
+// their claimed values at ζ.
+func (v *verifier) computeGammaKzg() error {
+	vk := v.vk
+
+	v.kzgDigests = []bn254.G1Affine{
+		v.getPoint("folded_h"),
+		v.getPoint("linearised_polynomial"),
+		v.point("l_com"),
+		v.point("r_com"),
+		v.point("o_com"),
+		vk.S[0],
+		vk.S[1],
+	}
+	v.kzgDigests = append(v.kzgDigests, vk.Qcp...)
+
+	v.kzgClaimedValues = []fr.Element{
+		v.scalar("quotient_polynomial_at_zeta"),
+		v.scalar("linearised_polynomial_at_zeta"),
+		v.scalar("l_at_zeta"),
+		v.scalar("r_at_zeta"),
+		v.scalar("o_at_zeta"),
+		v.scalar("s1_at_zeta"),
+		v.scalar("s2_at_zeta"),
+	}
+	for i := range vk.CommitmentConstraintIndexes {
+		v.kzgClaimedValues = append(v.kzgClaimedValues, v.scalarAt(v.field("openings_selector_commit_api_at_zeta", i)))
+	}
+
+	zeta := v.getFr("zeta")
+	transcript := appendFr([]byte("gamma"), &zeta)
+	for i := range v.kzgDigests {
+		transcript = appendPoint(transcript, &v.kzgDigests[i])
+	}
+	for i := range v.kzgClaimedValues {
+		transcript = appendFr(transcript, &v.kzgClaimedValues[i])
+	}
+	h := sha256.Sum256(transcript)
+
+	var gammaKzg fr.Element
+	gammaKzg.SetBytes(h[:])
+	v.setFr("gamma_kzg", &gammaKzg)
+
+	return nil
+}
+
+// -------- fold_state
+
+// foldState folds the digests and the claimed values hashed by compute_gamma_kzg
+// with the powers of γ. It also writes state_check_var, as a verifier generated
+// in debug mode does.
+func (v *verifier) foldState() error {
+	gammaKzg := v.getFr("gamma_kzg")
+
+	foldedDigests := v.kzgDigests[0]
+	foldedClaimedValues := v.kzgClaimedValues[0]
+
+	var accGamma, tmp fr.Element
+	accGamma.SetOne()
+	for i := 1; i < len(v.kzgDigests); i++ {
+		accGamma.Mul(&accGamma, &gammaKzg)
+		accMul(&foldedDigests, &v.kzgDigests[i], &accGamma)
+		tmp.Mul(&v.kzgClaimedValues[i], &accGamma)
+		foldedClaimedValues.Add(&foldedClaimedValues, &tmp)
+	}
+
+	v.setPoint("folded_digests", &foldedDigests)
+	v.setFr("folded_claimed_values", &foldedClaimedValues)
+	v.setFr("check_var", &gammaKzg)
+
+	return nil
+}
+
+// -------- batch_verify_multi_points
+
+// batchVerifyMultiPoints folds the opening at ζ and the opening at ζω with a
+// random and checks the result with a pairing.
+//
+// Verifier.sol negates a point (x,y) as (x,p-y), so the point at infinity (0,0)
+// becomes (0,p), which the next precompile rejects: this is mirrored here.
+func (v *verifier) batchVerifyMultiPoints() error {
+	vk := v.vk
+
+	// the random is the keccak of the first word of the state, alpha
+	alpha := v.state[slots["alpha"]].FillBytes(make([]byte, fr.Bytes))
+	var random fr.Element
+	random.SetBytes(crypto.Keccak256(alpha))
+
+	wZeta := v.point("batch_opening_at_zeta")
+	wZetaOmega := v.point("opening_at_zeta_omega")
+
+	foldedQuotients := wZeta
+	accMul(&foldedQuotients, &wZetaOmega, &random)
+
+	foldedDigests := v.getPoint("folded_digests")
+	z := v.point("grand_product_commitment")
+	accMul(&foldedDigests, &z, &random)
+
+	foldedEvals := v.getFr("folded_claimed_values")
+	zOmega := v.scalar("grand_product_at_zeta_omega")
+	zOmega.Mul(&zOmega, &random)
+	foldedEvals.Add(&foldedEvals, &zOmega)
+	v.setFr("folded_claimed_values", &foldedEvals)
+
+	_, _, g1, _ := bn254.Generators()
+	foldedEvalsCommit := ecMul(&g1, &foldedEvals)
+	if foldedEvalsCommit.IsInfinity() {
+		return reverts.New("PrecompileFailed", big.NewInt(6))
+	}
+	foldedEvalsCommit.Neg(&foldedEvalsCommit)
+	foldedDigests.Add(&foldedDigests, &foldedEvalsCommit)
+
+	zeta := v.getFr("zeta")
+	foldedPointsQuotients := ecMul(&wZeta, &zeta)
+	var zetaOmega fr.Element
+	zetaOmega.Mul(&zeta, &vk.Generator)
+	random.Mul(&random, &zetaOmega)
+	accMul(&foldedPointsQuotients, &wZetaOmega, &random)
+
+	foldedDigests.Add(&foldedDigests, &foldedPointsQuotients)
+	v.setPoint("folded_digests", &foldedDigests)
+
+	if foldedQuotients.IsInfinity() {
+		return reverts.New("PrecompileFailed", big.NewInt(8))
+	}
+	foldedQuotients.Neg(&foldedQuotients)
+
+	ok, err := bn254.PairingCheck(
+		[]bn254.G1Affine{foldedDigests, foldedQuotients},
+		[]bn254.G2Affine{vk.Kzg.G2[0], vk.Kzg.G2[1]},
+	)
+	if err != nil {
+		return reverts.New("PrecompileFailed", big.NewInt(8))
+	}
+	if !ok {
+		return reverts.New("PairingCheckFailed")
+	}
+	return nil
+}
+
+// -------- utils
+
+// pow returns x**e mod r, as the modexp precompile computes it
+func pow(x *fr.Element, e *big.Int) fr.Element {
+	var res fr.Element
+	res.Exp(*x, e)
+	return res
+}
+
+// ecMul returns [s]p, as the ecMul precompile computes it
+func ecMul(p *bn254.G1Affine, s *fr.Element) bn254.G1Affine {
+	var res bn254.G1Affine
+	res.ScalarMultiplication(p, s.BigInt(new(big.Int)))
+	return res
+}
+
+// accMul computes dst <- dst + [s]p, as point_acc_mul in Verifier.sol
+func accMul(dst, p *bn254.G1Affine, s *fr.Element) {
+	tmp := ecMul(p, s)
+	dst.Add(dst, &tmp)
+}
+
+// appendPoint appends x||y to dst, the point at infinity being (0,0)
+func appendPoint(dst []byte, p *bn254.G1Affine) []byte {
+	x, y := p.X.Bytes(), p.Y.Bytes()
+	dst = append(dst, x[:]...)
+	return append(dst, y[:]...)
+}
+
+func appendFr(dst []byte, x *fr.Element) []byte {
+	b := x.Bytes()
+	return append(dst, b[:]...)
+}
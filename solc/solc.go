@@ -0,0 +1,200 @@
+// Package solc compiles the generated sources with a local solc binary.
+package solc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Options configures the compilation. The zero value runs the solc found in
+// $PATH, with the optimizer and 200 runs, as the Makefile does.
+type Options struct {
+
+	// Path of the solc binary, "solc" by default
+	Path string
+
+	// OptimizeRuns is the --optimize-runs parameter of solc, 200 by default. A
+	// negative value disables the optimizer.
+	OptimizeRuns int
+}
+
+const (
+	defaultPath         = "solc"
+	defaultOptimizeRuns = 200
+)
+
+// Contract is a compiled contract.
+type Contract struct {
+
+	// Name of the contract, e.g. TestVerifier
+	Name string
+
+	// ABI is the JSON ABI of the contract
+	ABI string
+
+	// Bin is the creation code of the contract
+	Bin []byte
+
+	// BinRuntime is the code of the deployed contract
+	BinRuntime []byte
+
+	// SrcMapRuntime is the source map of BinRuntime, which maps each of its
+	// instructions to a range of one of the files of SourceList, see
+	// https://docs.soliditylang.org/en/latest/internals/source_mappings.html
+	SrcMapRuntime string
+
+	// SourceList are the files compiled, indexed as in the source maps
+	SourceList []string
+}
+
+// combinedOutput is the output of solc --combined-json
+// abi,bin,bin-runtime,srcmap-runtime
+type combinedOutput struct {
+	Contracts map[string]struct {
+		// a string before solc 0.8.10, a JSON array since
+		ABI           json.RawMessage `json:"abi"`
+		Bin           string          `json:"bin"`
+		BinRuntime    string          `json:"bin-runtime"`
+		SrcMapRuntime string          `json:"srcmap-runtime"`
+	} `json:"contracts"`
+	SourceList []string `json:"sourceList"`
+	Version    string   `json:"version"`
+}
+
+// Compile writes sources, which map a file name to its content, in a temporary
+// folder, compiles them, and returns the contracts they define sorted by name.
+// Libraries whose functions are all internal have no bytecode, they are skipped.
+func Compile(sources map[string][]byte, opts Options) ([]Contract, error) {
+
+	if opts.Path == "" {
+		opts.Path = defaultPath
+	}
+	if opts.OptimizeRuns == 0 {
+		opts.OptimizeRuns = defaultOptimizeRuns
+	}
+
+	dir, err := os.MkdirTemp("", "plonk-solidity-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	names := make([]string, 0, len(sources))
+	for name, content := range sources {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := []string{"--combined-json", "abi,bin,bin-runtime,srcmap-runtime"}
+	if opts.OptimizeRuns > 0 {
+		args = append(args, "--optimize", "--optimize-runs", strconv.Itoa(opts.OptimizeRuns))
+	}
+	args = append(args, names...)
+
+	cmd := exec.Command(opts.Path, args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w\n%s", opts.Path, err, stderr.String())
+	}
+
+	return parseCombinedOutput(stdout.Bytes())
+}
+
+// parseCombinedOutput decodes the output of solc --combined-json
+func parseCombinedOutput(data []byte) ([]Contract, error) {
+
+	var output combinedOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("decode solc output: %w", err)
+	}
+
+	var res []Contract
+	for key, c := range output.Contracts {
+		if c.Bin == "" {
+			continue
+		}
+		var abi string
+		if err := json.Unmarshal(c.ABI, &abi); err != nil {
+			abi = string(c.ABI)
+		}
+		bin, err := hexutil.Decode("0x" + c.Bin)
+		if err != nil {
+			return nil, fmt.Errorf("bytecode of %s: %w", key, err)
+		}
+		var binRuntime []byte
+		if c.BinRuntime != "" {
+			if binRuntime, err = hexutil.Decode("0x" + c.BinRuntime); err != nil {
+				return nil, fmt.Errorf("runtime bytecode of %s: %w", key, err)
+			}
+		}
+		// the keys are <file>:<contract>
+		res = append(res, Contract{
+			Name:          key[strings.LastIndex(key, ":")+1:],
+			ABI:           abi,
+			Bin:           bin,
+			BinRuntime:    binRuntime,
+			SrcMapRuntime: c.SrcMapRuntime,
+			SourceList:    output.SourceList,
+		})
+	}
+	if len(res) == 0 {
+		return nil, errors.New("solc output holds no contract")
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res, nil
+}
+
+// Version returns the version of the solc binary of opts, e.g. 0.8.19, without
+// the commit and the platform.
+func Version(opts Options) (string, error) {
+	if opts.Path == "" {
+		opts.Path = defaultPath
+	}
+	out, err := exec.Command(opts.Path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("%s --version: %w", opts.Path, err)
+	}
+	return parseVersion(out)
+}
+
+// parseVersion reads the version in the output of solc --version, whose last line
+// is Version: 0.8.19+commit.7dd6d404.Linux.g++
+func parseVersion(out []byte) (string, error) {
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Version: ") {
+			v := strings.TrimPrefix(line, "Version: ")
+			if i := strings.IndexByte(v, '+'); i >= 0 {
+				v = v[:i]
+			}
+			return v, nil
+		}
+	}
+	return "", errors.New("no version in the output of solc --version")
+}
+
+// Find returns the contract called name.
+func Find(contracts []Contract, name string) (*Contract, error) {
+	for i := range contracts {
+		if contracts[i].Name == name {
+			return &contracts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no contract %s in the solc output", name)
+}
@@ -0,0 +1,39 @@
+package solc
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+
+	out := []byte("solc, the solidity compiler commandline interface\nVersion: 0.8.19+commit.7dd6d404.Linux.g++\n")
+	v, err := parseVersion(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "0.8.19" {
+		t.Fatalf("version %q, expected 0.8.19", v)
+	}
+
+	if _, err := parseVersion([]byte("solc, the solidity compiler commandline interface\n")); err == nil {
+		t.Fatal("an output without version is accepted")
+	}
+}
+
+func TestParseCombinedOutput(t *testing.T) {
+
+	// a library with internal functions only has no bytecode
+	out := []byte(`{"contracts":{"Verifier.sol:PlonkVerifier":{"abi":[],"bin":"6080","bin-runtime":"60","srcmap-runtime":"0:10:1:-:0"},"Utils.sol:Utils":{"abi":[],"bin":""}},"sourceList":["Utils.sol","Verifier.sol"],"version":"0.8.19+commit.7dd6d404.Linux.g++"}`)
+	contracts, err := parseCombinedOutput(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contracts) != 1 || contracts[0].Name != "PlonkVerifier" || len(contracts[0].Bin) != 2 {
+		t.Fatalf("unexpected contracts %+v", contracts)
+	}
+	c := contracts[0]
+	if len(c.BinRuntime) != 1 || c.SrcMapRuntime != "0:10:1:-:0" || len(c.SourceList) != 2 || c.SourceList[1] != "Verifier.sol" {
+		t.Fatalf("unexpected runtime bytecode and source map %+v", c)
+	}
+	if _, err := Find(contracts, "Utils"); err == nil {
+		t.Fatal("found a contract without bytecode")
+	}
+}
@@ -0,0 +1,247 @@
+// Package srs loads the KZG structured reference string of a PLONK setup from
+// the transcript of a powers of tau ceremony.
+//
+// The G2 points of the SRS are hardcoded in Verifier.sol (g2_srs_0, g2_srs_1): a
+// verifier generated from an SRS whose secret τ is known, such as gnark's test
+// SRS, accepts forged proofs. A Transcript, which only ReadPtau returns, is the
+// provenance package tmpl requires to generate a production verifier.
+package srs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+// sections of a .ptau file
+const (
+	ptauHeader = 1
+	ptauTauG1  = 2
+	ptauTauG2  = 3
+)
+
+var ptauMagic = []byte("ptau")
+
+// ErrSRSTooSmall is returned when the transcript holds fewer powers of τ than the
+// circuit needs.
+var ErrSRSTooSmall = errors.New("the transcript holds too few powers of tau")
+
+// Transcript is an SRS read from a powers of tau transcript by ReadPtau.
+type Transcript struct {
+	srs kzg.SRS
+}
+
+// SRS returns the SRS of the transcript, to set a circuit up with.
+func (t *Transcript) SRS() *kzg.SRS {
+	return &t.srs
+}
+
+// Matches returns true if vk is the KZG verifying key of the transcript, e.g. the
+// Kzg field of a PLONK verifying key set up with t.SRS().
+func (t *Transcript) Matches(vk *kzg.VerifyingKey) bool {
+	return vk.G1.Equal(&t.srs.Vk.G1) &&
+		vk.G2[0].Equal(&t.srs.Vk.G2[0]) &&
+		vk.G2[1].Equal(&t.srs.Vk.G2[1])
+}
+
+type section struct {
+	offset int64
+	size   uint64
+}
+
+// ReadPtau reads the SRS of a .ptau file, the transcript format of snarkjs and
+// of the Perpetual Powers of Tau ceremony on BN254, either a raw transcript or a
+// prepared (phase 2) one. Only the first size G1 powers [τⁱ]G₁ are kept, see
+// Size; the file is read with seeks, the other points are not loaded.
+//
+// Every point is checked to be canonical and on the curve, the G2 points to be in
+// the subgroup, and e([τ]G₁, G₂) = e(G₁, [τ]G₂).
+func ReadPtau(r io.ReadSeeker, size uint64) (*Transcript, error) {
+
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(magic[:], ptauMagic) {
+		return nil, errors.New("not a ptau file")
+	}
+	var version, nbSections uint32
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &nbSections); err != nil {
+		return nil, err
+	}
+
+	// the sections are stored as type (uint32) || size (uint64) || data
+	sections := make(map[uint32]section)
+	for i := uint32(0); i < nbSections; i++ {
+		var sectionType uint32
+		var sectionSize uint64
+		if err := binary.Read(r, binary.LittleEndian, &sectionType); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, binary.LittleEndian, &sectionSize); err != nil {
+			return nil, err
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		sections[sectionType] = section{offset, sectionSize}
+		if _, err := r.Seek(int64(sectionSize), io.SeekCurrent); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range []uint32{ptauHeader, ptauTauG1, ptauTauG2} {
+		if _, ok := sections[t]; !ok {
+			return nil, fmt.Errorf("ptau file without section %d", t)
+		}
+	}
+
+	// header: n8 (uint32) || q (n8 bytes) || power (uint32) || ceremony power (uint32)
+	if _, err := r.Seek(sections[ptauHeader].offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	var n8 uint32
+	if err := binary.Read(r, binary.LittleEndian, &n8); err != nil {
+		return nil, err
+	}
+	if n8 != fp.Bytes {
+		return nil, fmt.Errorf("ptau file on a field of %d bytes, expected BN254", n8)
+	}
+	q := make([]byte, n8)
+	if _, err := io.ReadFull(r, q); err != nil {
+		return nil, err
+	}
+	if new(big.Int).SetBytes(reverse(q)).Cmp(fp.Modulus()) != 0 {
+		return nil, errors.New("ptau file on another curve than BN254")
+	}
+	var power uint32
+	if err := binary.Read(r, binary.LittleEndian, &power); err != nil {
+		return nil, err
+	}
+
+	// a transcript of power p holds 2ᵖ⁺¹-1 powers in G1 and 2ᵖ in G2
+	nbG1 := uint64(1)<<(power+1) - 1
+	if size > nbG1 {
+		return nil, fmt.Errorf("%w: %d powers in G1, %d needed", ErrSRSTooSmall, nbG1, size)
+	}
+	if size < 2 {
+		size = 2
+	}
+
+	var res kzg.SRS
+	res.Pk.G1 = make([]bn254.G1Affine, size)
+	if _, err := r.Seek(sections[ptauTauG1].offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 4*fp.Bytes)
+	for i := range res.Pk.G1 {
+		if _, err := io.ReadFull(r, buf[:2*fp.Bytes]); err != nil {
+			return nil, err
+		}
+		if err := readG1(&res.Pk.G1[i], buf); err != nil {
+			return nil, fmt.Errorf("[τ^%d]G1: %w", i, err)
+		}
+	}
+
+	if _, err := r.Seek(sections[ptauTauG2].offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	for i := range res.Vk.G2 {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		if err := readG2(&res.Vk.G2[i], buf); err != nil {
+			return nil, fmt.Errorf("[τ^%d]G2: %w", i, err)
+		}
+	}
+	res.Vk.G1 = res.Pk.G1[0]
+
+	if err := check(&res); err != nil {
+		return nil, err
+	}
+	return &Transcript{srs: res}, nil
+}
+
+// check checks that the SRS starts with the generators, and that its G1 and G2
+// points share the same τ
+func check(srs *kzg.SRS) error {
+	_, _, g1, g2 := bn254.Generators()
+	if !srs.Pk.G1[0].Equal(&g1) || !srs.Vk.G2[0].Equal(&g2) {
+		return errors.New("the SRS doesn't start with the generators of G1 and G2")
+	}
+
+	// e([τ]G₁, G₂) · e(-G₁, [τ]G₂) = 1
+	var minusG1 bn254.G1Affine
+	minusG1.Neg(&g1)
+	ok, err := bn254.PairingCheck(
+		[]bn254.G1Affine{srs.Pk.G1[1], minusG1},
+		[]bn254.G2Affine{g2, srs.Vk.G2[1]},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("the powers of tau in G1 and G2 don't match")
+	}
+	return nil
+}
+
+// readFp reads an element of Fp serialised in Montgomery form, little endian, as
+// snarkjs does
+func readFp(z *fp.Element, b []byte) error {
+	if new(big.Int).SetBytes(reverse(b[:fp.Bytes])).Cmp(fp.Modulus()) >= 0 {
+		return errors.New("coordinate not reduced")
+	}
+	// the Montgomery form of gnark-crypto uses the same R = 2²⁵⁶
+	for i := range z {
+		z[i] = binary.LittleEndian.Uint64(b[8*i:])
+	}
+	return nil
+}
+
+// readG1 reads x || y
+func readG1(p *bn254.G1Affine, b []byte) error {
+	if err := readFp(&p.X, b); err != nil {
+		return err
+	}
+	if err := readFp(&p.Y, b[fp.Bytes:]); err != nil {
+		return err
+	}
+	if !p.IsOnCurve() {
+		return errors.New("point not on the curve")
+	}
+	return nil
+}
+
+// readG2 reads x.A0 || x.A1 || y.A0 || y.A1
+func readG2(p *bn254.G2Affine, b []byte) error {
+	for i, z := range []*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1} {
+		if err := readFp(z, b[i*fp.Bytes:]); err != nil {
+			return err
+		}
+	}
+	if !p.IsOnCurve() || !p.IsInSubGroup() {
+		return errors.New("point not in the subgroup of G2")
+	}
+	return nil
+}
+
+// reverse returns b in reverse order, to read a little endian number with
+// big.Int.SetBytes
+func reverse(b []byte) []byte {
+	res := make([]byte, len(b))
+	for i := range b {
+		res[len(b)-1-i] = b[i]
+	}
+	return res
+}
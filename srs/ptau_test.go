@@ -0,0 +1,159 @@
+package srs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+)
+
+// ptau builds a transcript of the given power whose secret is tau, as snarkjs
+// writes it. The G2 powers are computed with tauG2 in place of tau, to write
+// inconsistent transcripts.
+func ptau(t *testing.T, power uint32, tau, tauG2 int64) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	write := func(v interface{}) {
+		if err := binary.Write(&buf, binary.LittleEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFp := func(z *fp.Element) {
+		for _, limb := range z {
+			write(limb)
+		}
+	}
+	writeSection := func(sectionType uint32, data []byte) {
+		write(sectionType)
+		write(uint64(len(data)))
+		buf.Write(data)
+	}
+
+	_, _, g1, g2 := bn254.Generators()
+	nbG1 := 1<<(power+1) - 1
+	nbG2 := 1 << power
+
+	buf.WriteString("ptau")
+	write(uint32(1)) // version
+	write(uint32(3)) // sections
+
+	// header
+	var header bytes.Buffer
+	q := fp.Modulus().Bytes()
+	binary.Write(&header, binary.LittleEndian, uint32(fp.Bytes))
+	header.Write(reverse(q))
+	binary.Write(&header, binary.LittleEndian, power)
+	binary.Write(&header, binary.LittleEndian, power)
+	writeSection(ptauHeader, header.Bytes())
+
+	// points, in a buffer of their own
+	points := func(n int, secret int64, f func(s *big.Int)) []byte {
+		start := buf.Len()
+		s := big.NewInt(1)
+		for i := 0; i < n; i++ {
+			f(s)
+			s.Mul(s, big.NewInt(secret))
+		}
+		res := append([]byte{}, buf.Bytes()[start:]...)
+		buf.Truncate(start)
+		return res
+	}
+	tauG1Section := points(nbG1, tau, func(s *big.Int) {
+		var p bn254.G1Affine
+		p.ScalarMultiplication(&g1, s)
+		writeFp(&p.X)
+		writeFp(&p.Y)
+	})
+	tauG2Section := points(nbG2, tauG2, func(s *big.Int) {
+		var p bn254.G2Affine
+		p.ScalarMultiplication(&g2, s)
+		writeFp(&p.X.A0)
+		writeFp(&p.X.A1)
+		writeFp(&p.Y.A0)
+		writeFp(&p.Y.A1)
+	})
+	writeSection(ptauTauG1, tauG1Section)
+	writeSection(ptauTauG2, tauG2Section)
+
+	return buf.Bytes()
+}
+
+func TestReadPtau(t *testing.T) {
+
+	const tau = 7
+	transcript, err := ReadPtau(bytes.NewReader(ptau(t, 3, tau, tau)), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srs := transcript.SRS()
+	if len(srs.Pk.G1) != 10 {
+		t.Fatalf("%d G1 powers, expected 10", len(srs.Pk.G1))
+	}
+	_, _, g1, g2 := bn254.Generators()
+	var expectedG1 bn254.G1Affine
+	expectedG1.ScalarMultiplication(&g1, big.NewInt(tau*tau*tau))
+	if !srs.Pk.G1[3].Equal(&expectedG1) {
+		t.Fatal("wrong [τ³]G₁")
+	}
+	var expectedG2 bn254.G2Affine
+	expectedG2.ScalarMultiplication(&g2, big.NewInt(tau))
+	if !srs.Vk.G2[1].Equal(&expectedG2) {
+		t.Fatal("wrong [τ]G₂")
+	}
+
+	if !transcript.Matches(&srs.Vk) {
+		t.Fatal("the transcript doesn't match its own verifying key")
+	}
+	other, err := ReadPtau(bytes.NewReader(ptau(t, 3, tau+1, tau+1)), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transcript.Matches(&other.SRS().Vk) {
+		t.Fatal("the transcript matches the verifying key of another secret")
+	}
+	if transcript.Matches(&kzg.VerifyingKey{}) {
+		t.Fatal("the transcript matches an empty verifying key")
+	}
+}
+
+func TestReadPtauErrors(t *testing.T) {
+
+	// 2⁴-1 powers in G1
+	_, err := ReadPtau(bytes.NewReader(ptau(t, 3, 7, 7)), 16)
+	if !errors.Is(err, ErrSRSTooSmall) {
+		t.Fatalf("expected ErrSRSTooSmall, got %v", err)
+	}
+
+	if _, err := ReadPtau(bytes.NewReader(ptau(t, 3, 7, 8)), 10); err == nil {
+		t.Fatal("powers of different secrets in G1 and G2 are accepted")
+	}
+
+	b := ptau(t, 3, 7, 7)
+	b[0] = 'x'
+	if _, err := ReadPtau(bytes.NewReader(b), 10); err == nil {
+		t.Fatal("a file without the ptau magic is accepted")
+	}
+
+	// first byte of the Y coordinate of [τ]G₁: magic, version, number of
+	// sections, then the header section and the type and size of section 2
+	b = ptau(t, 3, 7, 7)
+	offset := 4 + 4 + 4 + (4 + 8 + 4 + fp.Bytes + 4 + 4) + (4 + 8) + 2*fp.Bytes + fp.Bytes
+	b[offset] ^= 1
+	if _, err := ReadPtau(bytes.NewReader(b), 10); err == nil {
+		t.Fatal("a point off the curve is accepted")
+	}
+
+	// a coordinate equal to the modulus
+	b = ptau(t, 3, 7, 7)
+	copy(b[offset:], reverse(fp.Modulus().Bytes()))
+	if _, err := ReadPtau(bytes.NewReader(b), 10); err == nil {
+		t.Fatal("a non reduced coordinate is accepted")
+	}
+}
@@ -0,0 +1,10 @@
+package srs
+
+import "github.com/consensys/gnark-crypto/ecc"
+
+// Size returns the number of G1 powers of τ gnark's PLONK setup needs for a
+// constraint system of nbConstraints constraints and nbPublicVariables public
+// variables, as test.NewKZGSRS computes it.
+func Size(nbConstraints, nbPublicVariables int) uint64 {
+	return ecc.NextPowerOfTwo(uint64(nbConstraints+nbPublicVariables)) + 3
+}
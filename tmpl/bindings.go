@@ -0,0 +1,102 @@
+package tmpl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/plonk-solidity/solc"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// Bindings configures the compilation of the generated sources with a local solc
+// and the generation of the Go bindings of a contract, which the Makefile did
+// with solc and abigen.
+type Bindings struct {
+
+	// Solc configures the solc binary and the optimizer
+	Solc solc.Options
+
+	// Contract is the name of the Solidity contract to bind, TestVerifier by
+	// default
+	Contract string
+
+	// Package is the name of the Go package of the bindings, contract by default
+	Package string
+
+	// Type is the name of the Go type of the bindings, Contract by default
+	Type string
+
+	// GoFile is the path of the Go file of the bindings. Nothing is written if it
+	// is empty.
+	GoFile string
+
+	// ABIFolder is the folder in which <Contract>.abi and <Contract>.bin are
+	// written. Nothing is written if it is empty.
+	ABIFolder string
+}
+
+const (
+	defaultBindingsContract = "TestVerifier"
+	defaultBindingsPackage  = "contract"
+	defaultBindingsType     = "Contract"
+)
+
+// Artifacts are the outputs of the compilation of the contract to bind.
+type Artifacts struct {
+	Contract solc.Contract
+
+	// Go is the source of the bindings, as abigen would generate it
+	Go []byte
+}
+
+// Bind compiles the sources with solc and generates the Go bindings of the
+// contract selected by b, with go-ethereum's bind.Bind. The ABI, the bytecode and
+// the bindings are written where b says.
+func (s Sources) Bind(b Bindings) (*Artifacts, error) {
+
+	if b.Contract == "" {
+		b.Contract = defaultBindingsContract
+	}
+	if b.Package == "" {
+		b.Package = defaultBindingsPackage
+	}
+	if b.Type == "" {
+		b.Type = defaultBindingsType
+	}
+
+	contracts, err := solc.Compile(s, b.Solc)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := solc.Find(contracts, b.Contract)
+	if err != nil {
+		return nil, err
+	}
+
+	bin := hex.EncodeToString(contract.Bin)
+	code, err := bind.Bind([]string{b.Type}, []string{contract.ABI}, []string{bin}, nil, b.Package, bind.LangGo, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("bindings of %s: %w", b.Contract, err)
+	}
+	res := &Artifacts{Contract: *contract, Go: []byte(code)}
+
+	if b.ABIFolder != "" {
+		abiPath := filepath.Join(b.ABIFolder, b.Contract+".abi")
+		if err := os.WriteFile(abiPath, []byte(contract.ABI), 0644); err != nil {
+			return nil, err
+		}
+		binPath := filepath.Join(b.ABIFolder, b.Contract+".bin")
+		if err := os.WriteFile(binPath, []byte(bin), 0644); err != nil {
+			return nil, err
+		}
+	}
+	if b.GoFile != "" {
+		if err := os.WriteFile(b.GoFile, res.Go, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
@@ -1,6 +1,10 @@
 package tmpl
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
 	"text/template"
@@ -8,81 +12,233 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/debug"
+	"github.com/consensys/plonk-solidity/profile"
+	"github.com/consensys/plonk-solidity/reverts"
+)
+
+// names of the generated files with the default name, see Options.Files
+const (
+	VerifierFile     = "Verifier.sol"
+	TestVerifierFile = "TestVerifier.sol"
+	UtilsFile        = "Utils.sol"
 )
 
 type ExtendedProof struct {
 	bn254plonk.Proof
-	Pi []fr.Element
+	Pi      []fr.Element
+	Options Options
 }
 
-func GenerateVerifier(vk bn254plonk.VerifyingKey, proof bn254plonk.Proof, pi []fr.Element, folderOut string) error {
+type ExtendedVerifyingKey struct {
+	bn254plonk.VerifyingKey
+	Options Options
+}
+
+// ErrTestSRS is returned when a production verifier is generated without the
+// transcript of its SRS (Options.SRS), and without Options.AllowTestSRS: the
+// verifying key may come from gnark's test SRS, whose secret is known.
+var ErrTestSRS = errors.New("the SRS of the verifying key has no provenance: set SRS to the transcript it was read from, or AllowTestSRS to generate a production verifier anyway")
+
+// TestSRSWarning heads the Verifier.sol of a production verifier generated with
+// Options.AllowTestSRS and without Options.SRS.
+const TestSRSWarning = "WARNING: test verifier, generated without the transcript of its SRS. If the verifying key was set up with a test SRS, whose secret is known, it accepts forged proofs: do not deploy it."
+
+// ErrSRSMismatch is returned when the verifying key was not set up with the
+// transcript of Options.SRS.
+var ErrSRSMismatch = errors.New("the verifying key was not set up with the SRS of the transcript")
 
-	funcMap := template.FuncMap{
-		// The name "inc" is what the function will be called in the template text.
-		"inc": func(i int) int {
-			return i + 1
-		},
-		"frptr": func(x fr.Element) *fr.Element {
-			return &x
-		},
-		"fpptr": func(x fp.Element) *fp.Element {
-			return &x
-		},
-		"add": func(i, j int) int {
-			return i + j
-		},
+// Sources maps the name of a generated file to its content.
+type Sources map[string][]byte
+
+var funcMap = template.FuncMap{
+	// The name "inc" is what the function will be called in the template text.
+	"inc": func(i int) int {
+		return i + 1
+	},
+	"testSRSWarning": func() string {
+		return TestSRSWarning
+	},
+	"frptr": func(x fr.Element) *fr.Element {
+		return &x
+	},
+	"fpptr": func(x fp.Element) *fp.Element {
+		return &x
+	},
+	"add": func(i, j int) int {
+		return i + j
+	},
+	"isLibrary": func(k Kind) bool {
+		return k == Library
+	},
+	"isDebug": func(m Mode) bool {
+		return m == Debug
+	},
+	"isProfile": func(m Mode) bool {
+		return m == Profile
+	},
+	"div": func(i, j int) int {
+		return i / j
+	},
+	"stateLayout":         debug.StateLayout,
+	"stateSize":           debug.StateSize,
+	"debugPhases":         debug.Phases,
+	"debugEventID":        debug.EventID.Hex,
+	"profileSections":     profile.Sections,
+	"profileEnterEventID": profile.EnterEventID.Hex,
+	"profileExitEventID":  profile.ExitEventID.Hex,
+	"revertErrors": func() []reverts.Spec {
+		return reverts.Errors
+	},
+	"isPoint": func(f *calldata.Field) bool {
+		return f.Kind == calldata.Point
+	},
+	"proofLayout": calldata.ProofLayout,
+	"proofSize":   calldata.ProofSize,
+	"proofWords":  proofWords,
+	// offset in the bytes memory proof, whose first word is the size of the proof
+	"offset": func(i int) string {
+		return fmt.Sprintf("%#x", i+0x20)
+	},
+}
+
+// solidityWord is a word of the serialised proof, hardcoded in TestVerifier.sol
+type solidityWord struct {
+	Name  string
+	Value string
+}
+
+// proofWords lists the words of the serialised proof with their values, following
+// calldata.ProofLayout
+func proofWords(proof bn254plonk.Proof) []solidityWord {
+	data := calldata.MarshalProof(proof)
+	words := calldata.ProofWords(len(proof.Bsb22Commitments))
+	res := make([]solidityWord, len(words))
+	var v big.Int
+	for i, w := range words {
+		v.SetBytes(data[w.Offset : w.Offset+fr.Bytes])
+		res[i] = solidityWord{Name: w.Name, Value: v.String()}
 	}
+	return res
+}
 
-	tf := template.New("t").Funcs(funcMap)
+// execute renders the template text with data
+func execute(name, text string, data interface{}) ([]byte, error) {
+	t, err := template.New(name).Funcs(funcMap).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
 
-	{
-		t, err := tf.Parse(solidityVerifier)
-		if err != nil {
-			return err
-		}
-		out := filepath.Join(folderOut, "Verifier.sol")
-		fout, err := os.Create(out)
-		if err != nil {
-			return err
+// GenerateVerifierSources renders the sources of the verifier of vk: Verifier.sol
+// and Utils.sol, named after opts.Name (see Options.Files). Nothing is written on
+// disk. vk is checked with ValidateVerifyingKey first. A production verifier
+// requires opts.SRS, of which vk must be the key, or opts.AllowTestSRS:
+// ErrTestSRS is returned otherwise.
+func GenerateVerifierSources(vk bn254plonk.VerifyingKey, opts Options) (Sources, error) {
+
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateVerifyingKey(&vk); err != nil {
+		return nil, err
+	}
+	if opts.Mode == Production && !opts.AllowTestSRS {
+		if opts.SRS == nil {
+			return nil, ErrTestSRS
 		}
-		err = t.Execute(fout, vk)
-		if err != nil {
-			return err
+		if !opts.SRS.Matches(&vk.Kzg) {
+			return nil, ErrSRSMismatch
 		}
 	}
 
-	{
-		t, err := tf.Parse(solidityTestVerifier)
-		if err != nil {
-			return err
-		}
-		out := filepath.Join(folderOut, "TestVerifier.sol")
-		fout, err := os.Create(out)
-		if err != nil {
-			return err
-		}
-		eproof := ExtendedProof{proof, pi}
-		err = t.Execute(fout, eproof)
-		if err != nil {
-			return err
-		}
+	files := opts.Files()
+	verifier, err := execute(files.Verifier, solidityVerifier, ExtendedVerifyingKey{vk, opts})
+	if err != nil {
+		return nil, err
+	}
+	utilsSource, err := execute(files.Utils, utils, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	{
-		t, err := tf.Parse(utils)
-		if err != nil {
-			return err
-		}
-		out := filepath.Join(folderOut, "Utils.sol")
-		fout, err := os.Create(out)
-		if err != nil {
-			return err
-		}
-		err = t.Execute(fout, nil)
-		if err != nil {
+	return Sources{
+		files.Verifier: verifier,
+		files.Utils:    utilsSource,
+	}, nil
+}
+
+// GenerateTestVerifierSource renders TestVerifier.sol, the test harness in which
+// proof and pi are hardcoded. opts must be the options used to generate the
+// verifier. Nothing is written on disk.
+func GenerateTestVerifierSource(proof bn254plonk.Proof, pi []fr.Element, opts Options) ([]byte, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	return execute(opts.Files().TestVerifier, solidityTestVerifier, ExtendedProof{proof, pi, opts})
+}
+
+// GenerateSources renders the sources of the verifier of vk and of the test harness
+// (Verifier.sol, Utils.sol, TestVerifier.sol, see Options.Files). Nothing is
+// written on disk.
+func GenerateSources(vk bn254plonk.VerifyingKey, proof bn254plonk.Proof, pi []fr.Element, opts Options) (Sources, error) {
+
+	sources, err := GenerateVerifierSources(vk, opts)
+	if err != nil {
+		return nil, err
+	}
+	sources[opts.Files().TestVerifier], err = GenerateTestVerifierSource(proof, pi, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+// Write writes every source in folderOut, which must exist.
+func (s Sources) Write(folderOut string) error {
+	for name, content := range s {
+		if err := os.WriteFile(filepath.Join(folderOut, name), content, 0644); err != nil {
 			return err
 		}
 	}
-
 	return nil
 }
+
+// GenerateVerifier writes Verifier.sol, Utils.sol and TestVerifier.sol in
+// folderOut, with the default options. The verifying key has no SRS provenance
+// then: the verifier is a test verifier, generated with Options.AllowTestSRS, and
+// Verifier.sol starts with TestSRSWarning. It is kept for compatibility.
+//
+// Deprecated: use GenerateVerifierWithOptions, with Options.SRS.
+func GenerateVerifier(vk bn254plonk.VerifyingKey, proof bn254plonk.Proof, pi []fr.Element, folderOut string) error {
+	return GenerateVerifierWithOptions(vk, proof, pi, folderOut, Options{AllowTestSRS: true}, nil)
+}
+
+// GenerateVerifierWithOptions writes the verifier, its utils and its test
+// harness in folderOut, with opts; the files are named after opts.Name, see
+// Options.Files. If bindings is not nil, the sources are then compiled with a
+// local solc and the Go bindings of the contract they select are generated, see
+// Sources.Bind: they always match the sources just written.
+func GenerateVerifierWithOptions(vk bn254plonk.VerifyingKey, proof bn254plonk.Proof, pi []fr.Element, folderOut string, opts Options, bindings *Bindings) error {
+	sources, err := GenerateSources(vk, proof, pi, opts)
+	if err != nil {
+		return err
+	}
+	if err := sources.Write(folderOut); err != nil {
+		return err
+	}
+	if bindings == nil {
+		return nil
+	}
+	_, err = sources.Bind(*bindings)
+	return err
+}
@@ -0,0 +1,186 @@
+package tmpl
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/plonk"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+	"github.com/consensys/plonk-solidity/solc"
+)
+
+// testSRSVerifyingKey sets the first example up with gnark's test SRS
+func testSRSVerifyingKey(t *testing.T) *bn254plonk.VerifyingKey {
+	t.Helper()
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, circuits.Examples[0].Circuit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	srs, err := test.NewKZGSRS(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, vk, err := plonk.Setup(ccs, srs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vk.(*bn254plonk.VerifyingKey)
+}
+
+func TestGenerateVerifierSourcesTestSRS(t *testing.T) {
+
+	vk := testSRSVerifyingKey(t)
+
+	for _, kind := range []Kind{Library, Contract} {
+		_, err := GenerateVerifierSources(*vk, Options{Kind: kind})
+		if !errors.Is(err, ErrTestSRS) {
+			t.Errorf("production %s: expected ErrTestSRS, got %v", kind, err)
+		}
+		sources, err := GenerateVerifierSources(*vk, Options{Kind: kind, AllowTestSRS: true})
+		if err != nil {
+			t.Errorf("production %s with AllowTestSRS: %v", kind, err)
+			continue
+		}
+		if !strings.Contains(string(sources[VerifierFile]), TestSRSWarning) {
+			t.Errorf("production %s with AllowTestSRS: no warning", kind)
+		}
+	}
+
+	// debug and profile verifiers are never deployed
+	for _, mode := range []Mode{Debug, Profile} {
+		sources, err := GenerateVerifierSources(*vk, Options{Mode: mode})
+		if err != nil {
+			t.Errorf("%s: %v", mode, err)
+			continue
+		}
+		if strings.Contains(string(sources[VerifierFile]), TestSRSWarning) {
+			t.Errorf("%s: unexpected warning", mode)
+		}
+	}
+}
+
+func TestGenerateVerifierFiles(t *testing.T) {
+
+	vk := testSRSVerifyingKey(t)
+	proof := bn254plonk.Proof{}
+
+	// two verifiers in the same folder don't overwrite each other
+	folder := t.TempDir()
+	for _, name := range []string{"", "OtherVerifier"} {
+		opts := Options{Name: name, AllowTestSRS: true}
+		if err := GenerateVerifierWithOptions(*vk, proof, nil, folder, opts, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := map[string]string{
+		VerifierFile:             "import { Utils } from './Utils.sol';",
+		UtilsFile:                "library Utils {",
+		TestVerifierFile:         "contract TestVerifier {",
+		"OtherVerifier.sol":      "import { OtherVerifierUtils } from './OtherVerifierUtils.sol';",
+		"OtherVerifierUtils.sol": "library OtherVerifierUtils {",
+		"TestOtherVerifier.sol":  "contract TestOtherVerifier {",
+	}
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(expected) {
+		t.Errorf("%d files written, expected %d", len(entries), len(expected))
+	}
+	for file, content := range expected {
+		b, err := os.ReadFile(filepath.Join(folder, file))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !strings.Contains(string(b), content) {
+			t.Errorf("%s doesn't contain %q", file, content)
+		}
+	}
+
+	// names whose files are the ones of the default verifier
+	for _, name := range []string{"Verifier", "Utils", "TestVerifier"} {
+		if _, err := GenerateVerifierSources(*vk, Options{Name: name, AllowTestSRS: true}); err == nil {
+			t.Errorf("name %s: expected an error", name)
+		}
+	}
+
+	// without options, the SRS has no provenance: a test verifier is written,
+	// with a warning
+	folder = t.TempDir()
+	if err := GenerateVerifier(*vk, proof, nil, folder); err != nil {
+		t.Fatalf("GenerateVerifier: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(folder, VerifierFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), TestSRSWarning) {
+		t.Error("the Verifier.sol of GenerateVerifier has no warning")
+	}
+}
+
+// TestExternalVerify checks that the Verify of a contract is external, and that
+// the test harness calls it through this
+func TestExternalVerify(t *testing.T) {
+
+	vk := testSRSVerifyingKey(t)
+	opts := Options{Kind: Contract, AllowTestSRS: true}
+	sources, err := GenerateSources(*vk, bn254plonk.Proof{}, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify := regexp.MustCompile(`function Verify\(bytes memory proof, uint256\[\] memory public_inputs\)\s+external view returns\(bool\)`)
+	if !verify.Match(sources[VerifierFile]) {
+		t.Error("Verify is not external")
+	}
+	if n := strings.Count(string(sources[TestVerifierFile]), "this.Verify(proof, "); n != 2 {
+		t.Errorf("TestVerifier.sol calls this.Verify %d times, expected 2", n)
+	}
+}
+
+// TestCompileTwoVerifiers compiles verifiers of different names together, their
+// contracts must not clash
+func TestCompileTwoVerifiers(t *testing.T) {
+
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip("solc is not in $PATH")
+	}
+
+	vk := testSRSVerifyingKey(t)
+	sources := make(Sources)
+	var expected []string
+	for _, name := range []string{"", "OtherVerifier"} {
+		opts := Options{Name: name, Kind: Contract, AllowTestSRS: true}
+		s, err := GenerateSources(*vk, bn254plonk.Proof{}, nil, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for file, content := range s {
+			sources[file] = content
+		}
+		c := opts.Contracts()
+		expected = append(expected, c.Verifier, c.Utils, c.TestVerifier)
+	}
+
+	contracts, err := solc.Compile(sources, solc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range expected {
+		if _, err := solc.Find(contracts, name); err != nil {
+			t.Error(err)
+		}
+	}
+}
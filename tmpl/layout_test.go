@@ -0,0 +1,157 @@
+package tmpl
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/plonk-solidity/calldata"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+)
+
+var (
+	constantRegexp   = regexp.MustCompile(`uint256 constant (proof_\w+) = (0x[0-9a-f]+);`)
+	proofSizeRegexp  = regexp.MustCompile(`uint256 constant vk_proof_size = (\d+);`)
+	checkProofRegexp = regexp.MustCompile(`check_proof_(?:point|scalar)\(proof, (\w+)\);`)
+	structRegexp     = regexp.MustCompile(`uint256 (proof_\w+);`)
+	valueRegexp      = regexp.MustCompile(`proof\.(proof_\w+) = (\d+);`)
+	encodeRegexp     = regexp.MustCompile(`abi\.encodePacked\(res, proof\.(proof_\w+)\)`)
+)
+
+// TestProofLayout renders the sources of the examples, with 0, 1 and 3 BSB22
+// commitments, and checks that Verifier.sol and TestVerifier.sol follow
+// calldata.ProofLayout, as MarshalProof does.
+func TestProofLayout(t *testing.T) {
+
+	for _, name := range []string{"sb-fiat-shamir", "com-fiat-shamir", "multiple-commitments"} {
+		t.Run(name, func(t *testing.T) {
+
+			example, err := circuits.Get(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			setup, err := example.Setup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, pi, err := setup.Prove(example.Assignment())
+			if err != nil {
+				t.Fatal(err)
+			}
+			sources, err := GenerateSources(*setup.VK, *proof, pi, Options{AllowTestSRS: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			n := len(proof.Bsb22Commitments)
+			fields := calldata.ProofLayout(n)
+			words := calldata.ProofWords(n)
+			data := calldata.MarshalProof(*proof)
+			verifier := sources[VerifierFile]
+			testVerifier := sources[TestVerifierFile]
+
+			// the proof_* constants are the offsets of ProofLayout(n) in the bytes
+			// memory proof, after its length
+			constants := make(map[string]int)
+			for _, m := range constantRegexp.FindAllSubmatch(verifier, -1) {
+				v, err := strconv.ParseInt(string(m[2]), 0, 64)
+				if err != nil {
+					t.Fatal(err)
+				}
+				constants[string(m[1])] = int(v)
+			}
+			for _, w := range words {
+				if w.Field.Index >= 0 {
+					continue
+				}
+				if offset, ok := constants[w.Name]; !ok || offset != w.Offset+0x20 {
+					t.Errorf("%s = %#x, expected %#x", w.Name, offset, w.Offset+0x20)
+				}
+			}
+			// the fields of the commitments follow proof_openings_selector_commit_api_at_zeta
+			openings := constants["proof_openings_selector_commit_api_at_zeta"]
+			for i := range fields {
+				f := &fields[i]
+				switch {
+				case f.Index >= 0 && f.Name == "openings_selector_commit_api_at_zeta":
+					if expected := openings + f.Index*0x20; f.Offset+0x20 != expected {
+						t.Errorf("opening of commitment %d at %#x, Verifier.sol reads it at %#x", f.Index, f.Offset+0x20, expected)
+					}
+				case f.Index >= 0:
+					if expected := openings + n*0x20 + f.Index*0x40; f.Offset+0x20 != expected {
+						t.Errorf("commitment %d at %#x, Verifier.sol reads it at %#x", f.Index, f.Offset+0x20, expected)
+					}
+				}
+			}
+			if m := proofSizeRegexp.FindSubmatch(verifier); m == nil || string(m[1]) != strconv.Itoa(calldata.ProofSize(n)) {
+				t.Errorf("vk_proof_size doesn't match the %d bytes of ProofSize(%d)", calldata.ProofSize(n), n)
+			}
+
+			// check_proof checks every field, in the order of the layout
+			checks := checkProofRegexp.FindAllSubmatch(verifier, -1)
+			if len(checks) != len(fields) {
+				t.Fatalf("check_proof checks %d fields, the layout has %d", len(checks), len(fields))
+			}
+			for i, m := range checks {
+				expected := fields[i].Words()[0].Name
+				if fields[i].Index >= 0 {
+					expected = fmt.Sprintf("%#x", fields[i].Offset+0x20)
+				}
+				if string(m[1]) != expected {
+					t.Errorf("check_proof checks %s in place of %s", m[1], expected)
+				}
+			}
+
+			// TestVerifier.sol declares, sets and serialises the words in the order
+			// of the layout, with the values of MarshalProof
+			for _, re := range []*regexp.Regexp{structRegexp, valueRegexp, encodeRegexp} {
+				matches := re.FindAllSubmatch(testVerifier, -1)
+				if len(matches) != len(words) {
+					t.Fatalf("%s: %d words in TestVerifier.sol, expected %d", re, len(matches), len(words))
+				}
+				for i, m := range matches {
+					if string(m[1]) != words[i].Name {
+						t.Errorf("%s: word %d is %s, expected %s", re, i, m[1], words[i].Name)
+					}
+				}
+			}
+			var serialised []byte
+			for _, m := range valueRegexp.FindAllSubmatch(testVerifier, -1) {
+				v, ok := new(big.Int).SetString(string(m[2]), 10)
+				if !ok {
+					t.Fatalf("%s = %s is not a number", m[1], m[2])
+				}
+				serialised = append(serialised, v.FillBytes(make([]byte, 32))...)
+			}
+			if !bytes.Equal(serialised, data) {
+				t.Error("the proof of TestVerifier.sol is not MarshalProof(proof)")
+			}
+
+			// MarshalProof writes each field where Verifier.sol reads it
+			for i := range fields {
+				f := &fields[i]
+				offset := f.Offset
+				if f.Index < 0 {
+					offset = constants[f.Words()[0].Name] - 0x20
+				}
+				if f.Kind == calldata.Scalar {
+					b := f.Scalar(proof).Bytes()
+					if !bytes.Equal(data[offset:offset+fr.Bytes], b[:]) {
+						t.Errorf("%s is not at %#x", f.Name, offset)
+					}
+					continue
+				}
+				p := f.Point(proof)
+				x, y := p.X.Bytes(), p.Y.Bytes()
+				if !bytes.Equal(data[offset:offset+fp.Bytes], x[:]) || !bytes.Equal(data[offset+fp.Bytes:offset+2*fp.Bytes], y[:]) {
+					t.Errorf("%s is not at %#x", f.Name, offset)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package tmpl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/consensys/plonk-solidity/srs"
+)
+
+// Kind is the kind of Solidity unit the verifier is generated as.
+type Kind uint8
+
+const (
+	// Library is a library whose Verify function is internal, it is inlined in
+	// the contracts using it.
+	Library Kind = iota
+
+	// Contract is a deployable contract whose Verify function is external, the
+	// test harness calls it through this.Verify.
+	Contract
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Library:
+		return "library"
+	case Contract:
+		return "contract"
+	default:
+		return fmt.Sprintf("Kind(%d)", uint8(k))
+	}
+}
+
+// Mode selects the debug artifacts of the generated verifier.
+type Mode uint8
+
+const (
+	// Production removes every debug artifact, Verify is a view function.
+	Production Mode = iota
+
+	// Debug logs the state of Verify at the end of each phase of the
+	// verification, see package debug. Verify is not a view function.
+	Debug
+
+	// Profile marks the start and the end of each section of Verify with a log,
+	// to attribute its gas to the sections, see package profile. Verify is not a
+	// view function.
+	Profile
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Production:
+		return "production"
+	case Debug:
+		return "debug"
+	case Profile:
+		return "profile"
+	default:
+		return fmt.Sprintf("Mode(%d)", uint8(m))
+	}
+}
+
+// Options configures the generated sources. The zero value gives the default
+// output: library PlonkVerifier in production mode, pragma solidity ^0.8.4,
+// Apache-2.0 license.
+type Options struct {
+
+	// Name of the verifier library or contract, PlonkVerifier by default
+	Name string
+
+	// Pragma is the version constraint of the pragma solidity directive, ^0.8.4 by
+	// default. The verifier uses custom errors, which require solidity 0.8.4.
+	Pragma string
+
+	// License is the SPDX license identifier written at the top of every file,
+	// Apache-2.0 by default
+	License string
+
+	Kind Kind
+
+	Mode Mode
+
+	// SRS is the powers of tau transcript the verifying key was set up with, read
+	// by srs.ReadPtau. A production verifier is only generated from a verifying
+	// key of this transcript, unless AllowTestSRS is set.
+	SRS *srs.Transcript
+
+	// AllowTestSRS allows a production verifier to be generated without SRS, e.g.
+	// from a verifying key set up with gnark's test SRS, whose secret is known:
+	// such a verifier accepts forged proofs. Its Verifier.sol then starts with
+	// TestSRSWarning. Debug and profile verifiers are never meant to be deployed,
+	// they are always allowed.
+	AllowTestSRS bool
+}
+
+// Files are the names of the generated files.
+type Files struct {
+	Verifier     string
+	Utils        string
+	TestVerifier string
+}
+
+// Files returns the names of the files generated with o: VerifierFile, UtilsFile
+// and TestVerifierFile for the default name, <Name>.sol, <Name>Utils.sol and
+// Test<Name>.sol otherwise, so that verifiers of different names can be written
+// in the same folder.
+func (o Options) Files() Files {
+	if o.Name == "" || o.Name == defaultName {
+		return Files{VerifierFile, UtilsFile, TestVerifierFile}
+	}
+	return Files{
+		Verifier:     o.Name + ".sol",
+		Utils:        o.Name + "Utils.sol",
+		TestVerifier: "Test" + o.Name + ".sol",
+	}
+}
+
+// Contracts are the names of the generated Solidity units.
+type Contracts struct {
+	Verifier     string
+	Utils        string
+	TestVerifier string
+}
+
+// Contracts returns the names of the units generated with o, which follow
+// Files: Utils and TestVerifier for the default name, <Name>Utils and
+// Test<Name> otherwise, so that verifiers of different names can be compiled
+// together.
+func (o Options) Contracts() Contracts {
+	if o.Name == "" || o.Name == defaultName {
+		return Contracts{defaultName, "Utils", "TestVerifier"}
+	}
+	return Contracts{
+		Verifier:     o.Name,
+		Utils:        o.Name + "Utils",
+		TestVerifier: "Test" + o.Name,
+	}
+}
+
+const (
+	defaultName    = "PlonkVerifier"
+	defaultPragma  = "^0.8.4"
+	defaultLicense = "Apache-2.0"
+)
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// withDefaults returns a copy of o where the unset fields are set to their
+// default value, and checks that the result can be rendered.
+func (o Options) withDefaults() (Options, error) {
+
+	if o.Name == "" {
+		o.Name = defaultName
+	}
+	if o.Pragma == "" {
+		o.Pragma = defaultPragma
+	}
+	if o.License == "" {
+		o.License = defaultLicense
+	}
+
+	if !identifier.MatchString(o.Name) {
+		return o, fmt.Errorf("invalid verifier name %q", o.Name)
+	}
+	if o.Name == "Utils" || o.Name == "TestVerifier" {
+		return o, fmt.Errorf("verifier name %q clashes with a generated contract", o.Name)
+	}
+	if o.Name != defaultName {
+		// the files of the default verifier may be in the same folder
+		for _, f := range []string{o.Files().Verifier, o.Files().Utils, o.Files().TestVerifier} {
+			if f == VerifierFile || f == UtilsFile || f == TestVerifierFile {
+				return o, fmt.Errorf("verifier name %q clashes with the generated file %s", o.Name, f)
+			}
+		}
+	}
+	if strings.ContainsAny(o.Pragma, ";\n") {
+		return o, fmt.Errorf("invalid pragma %q", o.Pragma)
+	}
+	if strings.ContainsAny(o.License, "\n") {
+		return o, fmt.Errorf("invalid license %q", o.License)
+	}
+	if o.Kind != Library && o.Kind != Contract {
+		return o, fmt.Errorf("unknown kind %s", o.Kind)
+	}
+	if o.Mode != Production && o.Mode != Debug && o.Mode != Profile {
+		return o, fmt.Errorf("unknown mode %s", o.Mode)
+	}
+
+	return o, nil
+}
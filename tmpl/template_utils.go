@@ -1,12 +1,12 @@
 package tmpl
 
-const utils = `
+const utils = `// SPDX-License-Identifier: {{ .License }}
 // It has not been audited and is provided as-is, we make no guarantees or warranties to its safety and reliability. 
 // 
 // According to https://eprint.iacr.org/archive/2019/953/1585767119.pdf
-pragma solidity ^0.8.0;
+pragma solidity {{ .Pragma }};
 
-library Utils {
+library {{ .Contracts.Utils }} {
 
     uint256 constant r_mod = 21888242871839275222246405745257275088548364400416034343698204186575808495617;
 
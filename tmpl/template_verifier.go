@@ -1,14 +1,22 @@
 package tmpl
 
-const solidityVerifier = `pragma solidity ^0.8.0;
+const solidityVerifier = `// SPDX-License-Identifier: {{ .Options.License }}
+{{- $debug := isDebug .Options.Mode }}
+{{- $profile := isProfile .Options.Mode }}
+{{- if and (not (or $debug $profile)) .Options.AllowTestSRS (not .Options.SRS) }}
+//
+// {{ testSRSWarning }}
+//
+{{- end }}
+pragma solidity {{ .Options.Pragma }};
 
 pragma experimental ABIEncoderV2;
 
-import {Utils} from './Utils.sol';
+import { {{ .Options.Contracts.Utils }} } from './{{ .Options.Files.Utils }}';
 
-library PlonkVerifier {
+{{ .Options.Kind }} {{ .Options.Name }} {
 
-  using Utils for *;
+  using {{ .Options.Contracts.Utils }} for *;
   uint256 constant r_mod = 21888242871839275222246405745257275088548364400416034343698204186575808495617;
   uint256 constant p_mod = 21888242871839275222246405745257275088696311157297823662689037894645226208583;
   {{ range $index, $element := .Kzg.G2 }}
@@ -56,101 +64,74 @@ library PlonkVerifier {
   {{ end }}
   uint256 constant vk_nb_commitments_commit_api = {{ len .CommitmentConstraintIndexes }};
 
+  // expected sizes of the inputs of Verify
+  uint256 constant vk_nb_public_inputs = {{ .NbPublicVariables }};
+  uint256 constant vk_proof_size = {{ proofSize (len .CommitmentConstraintIndexes) }};
+
   // ------------------------------------------------
 
   // offset proof
-  uint256 constant proof_l_com_x = 0x20;
-  uint256 constant proof_l_com_y = 0x40;
-  uint256 constant proof_r_com_x = 0x60;
-  uint256 constant proof_r_com_y = 0x80;
-  uint256 constant proof_o_com_x = 0xa0;
-  uint256 constant proof_o_com_y = 0xc0;
-
-  // h = h_0 + x^{n+2}h_1 + x^{2(n+2)}h_2
-  uint256 constant proof_h_0_x = 0xe0; 
-  uint256 constant proof_h_0_y = 0x100;
-  uint256 constant proof_h_1_x = 0x120;
-  uint256 constant proof_h_1_y = 0x140;
-  uint256 constant proof_h_2_x = 0x160;
-  uint256 constant proof_h_2_y = 0x180;
-
-  // wire values at zeta
-  uint256 constant proof_l_at_zeta = 0x1a0;
-  uint256 constant proof_r_at_zeta = 0x1c0;
-  uint256 constant proof_o_at_zeta = 0x1e0;
-
-  //uint256[STATE_WIDTH-1] permutation_polynomials_at_zeta; // Sσ1(zeta),Sσ2(zeta)
-  uint256 constant proof_s1_at_zeta = 0x200; // Sσ1(zeta)
-  uint256 constant proof_s2_at_zeta = 0x220; // Sσ2(zeta)
-
-  //Bn254.G1Point grand_product_commitment;                 // [z(x)]
-  uint256 constant proof_grand_product_commitment_x = 0x240;
-  uint256 constant proof_grand_product_commitment_y = 0x260;
-
-  uint256 constant proof_grand_product_at_zeta_omega = 0x280;                    // z(w*zeta)
-  uint256 constant proof_quotient_polynomial_at_zeta = 0x2a0;                    // t(zeta)
-  uint256 constant proof_linearised_polynomial_at_zeta = 0x2c0;               // r(zeta)
-
-  // Folded proof for the opening of H, linearised poly, l, r, o, s_1, s_2, qcp
-  uint256 constant proof_batch_opening_at_zeta_x = 0x2e0;            // [Wzeta]
-  uint256 constant proof_batch_opening_at_zeta_y = 0x300;
-
-  //Bn254.G1Point opening_at_zeta_omega_proof;      // [Wzeta*omega]
-  uint256 constant proof_opening_at_zeta_omega_x = 0x320;
-  uint256 constant proof_opening_at_zeta_omega_y = 0x340;
-  
-  uint256 constant proof_openings_selector_commit_api_at_zeta = 0x360;
+  {{- range $field := proofLayout 0 }}
+  {{- if $field.Comment }}
+
+  // {{ $field.Comment }}
+  {{- end }}
+  {{- range $field.Words }}
+  uint256 constant {{ .Name }} = {{ offset .Offset }};
+  {{- end }}
+  {{- end }}
+
+  uint256 constant proof_openings_selector_commit_api_at_zeta = {{ offset (proofSize 0) }};
   // -> next part of proof is 
   // [ openings_selector_commits || commitments_wires_commit_api]
 
   // -------- offset state
-
-  // challenges to check the claimed quotient
-  uint256 constant state_alpha = 0x00;
-  uint256 constant state_beta = 0x20;
-  uint256 constant state_gamma = 0x40;
-  uint256 constant state_zeta = 0x60;
-
-  // challenges related to KZG
-  uint256 constant state_sv = 0x80;
-  uint256 constant state_su = 0xa0;
-
-  // reusable value
-  uint256 constant state_alpha_square_lagrange = 0xc0;
-
-  // commitment to H
-  // Bn254.G1Point folded_h;
-  uint256 constant state_folded_h_x = 0xe0;
-  uint256 constant state_folded_h_y = 0x100;
-
-  // commitment to the linearised polynomial
-  uint256 constant state_linearised_polynomial_x = 0x120;
-  uint256 constant state_linearised_polynomial_y = 0x140;
-
-  // Folded proof for the opening of H, linearised poly, l, r, o, s_1, s_2, qcp
-  uint256 constant state_folded_claimed_values = 0x160;
-
-  // folded digests of H, linearised poly, l, r, o, s_1, s_2, qcp
-  uint256 constant state_folded_digests_x = 0x180;
-  uint256 constant state_folded_digests_y = 0x1a0;
-
-  uint256 constant state_pi = 0x1c0;
-
-  uint256 constant state_zeta_power_n_minus_one = 0x1e0;
-  uint256 constant state_alpha_square_lagrange_one = 0x200;
-
-  uint256 constant state_gamma_kzg = 0x220;
-
-  uint256 constant state_success = 0x240;
-  uint256 constant state_check_var = 0x260; // /!\ this slot is used for debugging only
-
-
-  uint256 constant state_last_mem = 0x280;
-
-  event PrintUint256(uint256 a);
+  {{- range stateLayout }}
+  {{- if or $debug (not .DebugOnly) }}
+  {{- if .Comment }}
+
+  // {{ .Comment }}
+  {{- end }}
+  uint256 constant state_{{ .Name }} = {{ printf "%#x" .Offset }};
+  {{- end }}
+  {{- end }}
+
+  uint256 constant state_last_mem = {{ printf "%#x" (stateSize $debug) }};
+  {{- if $debug }}
+
+  // -------- debug
+
+  // the state is logged at the end of each phase of Verify, see package debug
+  event PlonkDebugState(uint256 indexed phase, uint256[{{ div (stateSize $debug) 32 }}] state);
+  uint256 constant debug_state_event = {{ debugEventID }};
+  {{- range debugPhases }}
+  uint256 constant debug_phase_{{ . }} = {{ printf "%d" . }};
+  {{- end }}
+  {{- end }}
+  {{- if $profile }}
+
+  // -------- profile
+
+  // Verify logs the start and the end of its sections, see package profile
+  event PlonkProfileEnter(uint256 indexed section);
+  event PlonkProfileExit(uint256 indexed section);
+  uint256 constant profile_enter_event = {{ profileEnterEventID }};
+  uint256 constant profile_exit_event = {{ profileExitEventID }};
+  {{- range profileSections }}
+  uint256 constant profile_section_{{ . }} = {{ printf "%d" . }};
+  {{- end }}
+  {{- end }}
+
+  // -------- errors, see package reverts
+  {{- range revertErrors }}
+
+  // {{ .Comment }}
+  error {{ .Name }}({{ range $i, $arg := .Args }}{{ if $i }}, {{ end }}uint256 {{ $arg }}{{ end }});
+  uint256 constant {{ .Constant }} = {{ .SelectorWord }};
+  {{- end }}
 
   function derive_gamma_beta_alpha_zeta(bytes memory proof, uint256[] memory public_inputs)
-  internal returns(uint256, uint256, uint256, uint256) {
+  internal{{ if not $profile }} view{{ end }} returns(uint256, uint256, uint256, uint256) {
 
     uint256 gamma;
     uint256 beta;
@@ -161,17 +142,41 @@ library PlonkVerifier {
 
       let mem := mload(0x40)
 
+      {{ if $profile -}}
+      log2(0, 0, profile_enter_event, profile_section_derive_gamma)
+      {{ end -}}
       derive_gamma(proof, public_inputs)
       gamma := mload(mem)
+      {{- if $profile }}
+      log2(0, 0, profile_exit_event, profile_section_derive_gamma)
+      {{- end }}
 
+      {{ if $profile -}}
+      log2(0, 0, profile_enter_event, profile_section_derive_beta)
+      {{ end -}}
       derive_beta(proof, gamma)
       beta := mload(mem)
+      {{- if $profile }}
+      log2(0, 0, profile_exit_event, profile_section_derive_beta)
+      {{- end }}
 
+      {{ if $profile -}}
+      log2(0, 0, profile_enter_event, profile_section_derive_alpha)
+      {{ end -}}
       derive_alpha(proof, beta)
       alpha := mload(mem)
+      {{- if $profile }}
+      log2(0, 0, profile_exit_event, profile_section_derive_alpha)
+      {{- end }}
 
+      {{ if $profile -}}
+      log2(0, 0, profile_enter_event, profile_section_derive_zeta)
+      {{ end -}}
       derive_zeta(proof, alpha)
       zeta := mload(mem)
+      {{- if $profile }}
+      log2(0, 0, profile_exit_event, profile_section_derive_zeta)
+      {{- end }}
 
       gamma := mod(gamma, r_mod)
       beta := mod(beta, r_mod)
@@ -245,7 +250,9 @@ library PlonkVerifier {
 
         let size := add(0x2c5, mul(mload(pub_inputs), 0x20)) // 0x2c5 = 22*32+5
         size := add(size, mul(vk_nb_commitments_commit_api, 0x40))
-        pop(staticcall(sub(gas(), 2000), 0x2, add(mPtr, 0x1b), size, mPtr, 0x20)) //0x1b -> 000.."gamma"
+        if iszero(staticcall(sub(gas(), 2000), 0x2, add(mPtr, 0x1b), size, mPtr, 0x20)) { //0x1b -> 000.."gamma"
+          revert_precompile_failed(2)
+        }
       }
 
       function derive_beta(aproof, prev_challenge){
@@ -253,7 +260,9 @@ library PlonkVerifier {
         // beta
         mstore(mPtr, 0x62657461) // "beta"
         mstore(add(mPtr, 0x20), prev_challenge)
-        pop(staticcall(sub(gas(), 2000), 0x2, add(mPtr, 0x1c), 0x24, mPtr, 0x20)) //0x1b -> 000.."gamma"
+        if iszero(staticcall(sub(gas(), 2000), 0x2, add(mPtr, 0x1c), 0x24, mPtr, 0x20)) { //0x1b -> 000.."gamma"
+          revert_precompile_failed(2)
+        }
       }
 
       // alpha depends on the previous challenge (beta) and on the commitment to the grand product polynomial
@@ -264,7 +273,9 @@ library PlonkVerifier {
         mstore(add(mPtr, 0x20), prev_challenge)
         mstore(add(mPtr, 0x40), mload(add(aproof, proof_grand_product_commitment_x)))
         mstore(add(mPtr, 0x60), mload(add(aproof, proof_grand_product_commitment_y)))
-        pop(staticcall(sub(gas(), 2000), 0x2, add(mPtr, 0x1b), 0x65, mPtr, 0x20)) //0x1b -> 000.."gamma"
+        if iszero(staticcall(sub(gas(), 2000), 0x2, add(mPtr, 0x1b), 0x65, mPtr, 0x20)) { //0x1b -> 000.."gamma"
+          revert_precompile_failed(2)
+        }
       }
       
       // zeta depends on the previous challenge (alpha) and on the commitment to the quotient polynomial
@@ -279,7 +290,15 @@ library PlonkVerifier {
         mstore(add(mPtr, 0xa0), mload(add(aproof, proof_h_1_y)))
         mstore(add(mPtr, 0xc0), mload(add(aproof, proof_h_2_x)))
         mstore(add(mPtr, 0xe0), mload(add(aproof, proof_h_2_y)))
-        pop(staticcall(sub(gas(), 2000), 0x2, add(mPtr, 0x1c), 0xe4, mPtr, 0x20))
+        if iszero(staticcall(sub(gas(), 2000), 0x2, add(mPtr, 0x1c), 0xe4, mPtr, 0x20)) {
+          revert_precompile_failed(2)
+        }
+      }
+
+      function revert_precompile_failed(precompile) {
+        mstore(0x00, error_precompile_failed)
+        mstore(0x04, precompile)
+        revert(0x00, 0x24)
       }
     }
 
@@ -289,7 +308,7 @@ library PlonkVerifier {
   // read the commitments to the wires related to the commit api and store them in wire_commitments.
   // The commitments are points on Bn254(Fp) so they are stored on 2 uint256.
   function load_wire_commitments_commit_api(uint256[] memory wire_commitments, bytes memory proof)
-  internal {
+  internal pure {
     assembly {
       let w := add(wire_commitments, 0x20)
       let p := add(proof, proof_openings_selector_commit_api_at_zeta)
@@ -311,7 +330,7 @@ library PlonkVerifier {
   // * ω = vk_omega (generator of the multiplicative cyclic group of order n in (ℤ/rℤ)*)
   // * ζ = zeta (challenge derived with Fiat Shamir)
   function compute_ith_lagrange_at_z(uint256 zeta, uint256 i) 
-  internal returns (uint256) {
+  internal view returns (uint256) {
 
     uint256 res;
     assembly {
@@ -325,10 +344,18 @@ library PlonkVerifier {
           mstore(add(mPtr, 0x60), x)
           mstore(add(mPtr, 0x80), e)
           mstore(add(mPtr, 0xa0), r_mod)
-          pop(staticcall(sub(gas(), 2000),0x05,mPtr,0xc0,0x00,0x20))
+          if iszero(staticcall(sub(gas(), 2000),0x05,mPtr,0xc0,0x00,0x20)) {
+            revert_precompile_failed(5)
+          }
           result := mload(0x00)
       }
 
+      function revert_precompile_failed(precompile) {
+        mstore(0x00, error_precompile_failed)
+        mstore(0x04, precompile)
+        revert(0x00, 0x24)
+      }
+
       let w := pow_local(vk_omega,i) // w**i
       i := addmod(zeta, sub(r_mod, w), r_mod) // z-w**i
       zeta := pow_local(zeta, vk_domain_size) // z**n
@@ -346,7 +373,7 @@ library PlonkVerifier {
         bytes memory proof,
         uint256[] memory public_inputs,
         uint256 zeta
-    ) internal returns (uint256) {
+    ) internal{{ if not $profile }} view{{ end }} returns (uint256) {
 
       // evaluation of Z=Xⁿ⁻¹ at ζ
       // uint256 zeta_power_n_minus_one = Fr.pow(zeta, vk_domain_size);
@@ -362,7 +389,13 @@ library PlonkVerifier {
 
         function sum_pi_wo_api_commit(ins, n, z) {
           let li := mload(0x40)
+          {{- if $profile }}
+          log2(0, 0, profile_enter_event, profile_section_batch_compute_lagranges_at_z)
+          {{- end }}
           batch_compute_lagranges_at_z(z, n, li)
+          {{- if $profile }}
+          log2(0, 0, profile_exit_event, profile_section_batch_compute_lagranges_at_z)
+          {{- end }}
           let res := 0
           let tmp := 0
           for {let i:=0} lt(i,n) {i:=add(i,1)}
@@ -392,7 +425,13 @@ library PlonkVerifier {
             _w := mulmod(_w, vk_omega, r_mod)
             _mPtr := add(_mPtr, 0x20)
           }
+          {{- if $profile }}
+          log2(0, 0, profile_enter_event, profile_section_batch_invert)
+          {{- end }}
           batch_invert(mPtr, n, _mPtr)
+          {{- if $profile }}
+          log2(0, 0, profile_exit_event, profile_section_batch_invert)
+          {{- end }}
           _mPtr := mPtr
           _w := 1
           for {let i:=0} lt(i,n) {i:=add(i,1)}
@@ -446,10 +485,18 @@ library PlonkVerifier {
           mstore(add(mPtr, 0x60), x)
           mstore(add(mPtr, 0x80), e)
           mstore(add(mPtr, 0xa0), r_mod)
-          pop(staticcall(sub(gas(), 2000),0x05,mPtr,0xc0,mPtr,0x20))
+          if iszero(staticcall(sub(gas(), 2000),0x05,mPtr,0xc0,mPtr,0x20)) {
+            revert_precompile_failed(5)
+          }
           res := mload(mPtr)
         }
 
+        function revert_precompile_failed(precompile) {
+          mstore(0x00, error_precompile_failed)
+          mstore(0x04, precompile)
+          revert(0x00, 0x24)
+        }
+
         zeta_power_n_minus_one := pow(zeta, vk_domain_size, mload(0x40))
         zeta_power_n_minus_one := addmod(zeta_power_n_minus_one, sub(r_mod, 1), r_mod)
       }
@@ -467,7 +514,13 @@ library PlonkVerifier {
 
       for (uint256 i=0; i<vk_nb_commitments_commit_api; i++){
           
-          uint256 hash_res = Utils.hash_fr(wire_committed_commitments[2*i], wire_committed_commitments[2*i+1]);
+          {{ if $profile -}}
+          assembly { log2(0, 0, profile_enter_event, profile_section_hash_fr) }
+          {{ end -}}
+          uint256 hash_res = {{ .Options.Contracts.Utils }}.hash_fr(wire_committed_commitments[2*i], wire_committed_commitments[2*i+1]);
+          {{- if $profile }}
+          assembly { log2(0, 0, profile_exit_event, profile_section_hash_fr) }
+          {{- end }}
           uint256 a = compute_ith_lagrange_at_z(zeta, commitment_indices[i]+public_inputs.length);
           assembly {
             a := mulmod(hash_res, a, r_mod)
@@ -479,21 +532,106 @@ library PlonkVerifier {
       return pi;
     }
 
+  // reverts if the sizes of the proof or of the public inputs don't match the vk (a
+  // short proof would make Verify read the memory following it), or if a public input
+  // is not reduced mod r: x and x+r stand for the same field element, accepting both
+  // would make the public inputs malleable.
+  function check_inputs(bytes memory proof, uint256[] memory public_inputs)
+  internal pure {
+    if (proof.length != vk_proof_size) {
+      revert WrongProofSize(proof.length, vk_proof_size);
+    }
+    if (public_inputs.length != vk_nb_public_inputs) {
+      revert WrongNumberOfPublicInputs(public_inputs.length, vk_nb_public_inputs);
+    }
+    for (uint256 i=0; i<public_inputs.length; i++) {
+      if (public_inputs[i] >= r_mod) {
+        revert PublicInputNotReduced(i);
+      }
+    }
+    check_proof(proof);
+  }
+
+  // reverts if an element of the proof is not canonical: the scalars must be reduced
+  // mod r, the coordinates mod p, and the points must be on Bn254 or be (0,0), which
+  // the precompiles interpret as the point at infinity.
+  function check_proof(bytes memory proof)
+  internal pure {
+    {{- range proofLayout (len .CommitmentConstraintIndexes) }}
+    {{- $word := index .Words 0 }}
+    check_proof_{{ if isPoint . }}point{{ else }}scalar{{ end }}(proof, {{ if lt .Index 0 }}{{ $word.Name }}{{ else }}{{ offset .Offset }}{{ end }});
+    {{- if ge .Index 0 }} // {{ $word.Name }}{{ end }}
+    {{- end }}
+  }
+
+  function check_proof_scalar(bytes memory proof, uint256 offset)
+  internal pure {
+    uint256 s;
+    assembly {
+      s := mload(add(proof, offset))
+    }
+    if (s >= r_mod) {
+      revert ProofScalarNotReduced(offset);
+    }
+  }
+
+  // y² = x³ + 3 on Bn254
+  function check_proof_point(bytes memory proof, uint256 offset)
+  internal pure {
+    uint256 x;
+    uint256 y;
+    assembly {
+      x := mload(add(proof, offset))
+      y := mload(add(proof, add(offset, 0x20)))
+    }
+    if (x >= p_mod || y >= p_mod) {
+      revert ProofCoordinateNotReduced(offset);
+    }
+    if (x == 0 && y == 0) {
+      return;
+    }
+    uint256 x3 = mulmod(mulmod(x, x, p_mod), x, p_mod);
+    if (mulmod(y, y, p_mod) != addmod(x3, 3, p_mod)) {
+      revert ProofPointNotOnCurve(offset);
+    }
+  }
+
+  // Verify returns true if proof is a valid proof for public_inputs, it reverts with
+  // one of the errors above otherwise.
   function Verify(bytes memory proof, uint256[] memory public_inputs) 
-  internal returns(bool) {
+  {{ if isLibrary .Options.Kind }}internal{{ else }}external{{ end }}{{ if not (or $debug $profile) }} view{{ end }} returns(bool) {
 
     uint256 gamma;
     uint256 beta;
     uint256 alpha;
     uint256 zeta;
+    {{- if $profile }}
+
+    assembly { log2(0, 0, profile_enter_event, profile_section_Verify) }
+    assembly { log2(0, 0, profile_enter_event, profile_section_check_inputs) }
+    {{- end }}
+
+    check_inputs(proof, public_inputs);
+    {{- if $profile }}
+    assembly { log2(0, 0, profile_exit_event, profile_section_check_inputs) }
+    assembly { log2(0, 0, profile_enter_event, profile_section_derive_gamma_beta_alpha_zeta) }
+    {{- end }}
 
     (gamma, beta, alpha, zeta) = derive_gamma_beta_alpha_zeta(proof, public_inputs);
+    {{- if $profile }}
+    assembly { log2(0, 0, profile_exit_event, profile_section_derive_gamma_beta_alpha_zeta) }
+    assembly { log2(0, 0, profile_enter_event, profile_section_compute_pi) }
+    {{- end }}
 
     uint256 pi = compute_pi(proof, public_inputs, zeta);
+    {{- if $profile }}
+    assembly { log2(0, 0, profile_exit_event, profile_section_compute_pi) }
+    {{- end }}
 
-    uint256 check;
+    {{- if $debug }}
 
-    bool success = false;
+    uint256 check;
+    {{- end }}
 
     assembly {
 
@@ -503,18 +641,85 @@ library PlonkVerifier {
       mstore(add(mem, state_zeta), zeta)
       mstore(add(mem, state_beta), beta)
       mstore(add(mem, state_pi), pi)
+      {{- if $debug }}
+      log2(mem, state_last_mem, debug_state_event, debug_phase_challenges)
+      {{- end }}
 
+      {{ if $profile -}}
+      log2(0, 0, profile_enter_event, profile_section_compute_alpha_square_lagrange_0)
+      {{ end -}}
       compute_alpha_square_lagrange_0()
+      {{- if $profile }}
+      log2(0, 0, profile_exit_event, profile_section_compute_alpha_square_lagrange_0)
+      {{- end }}
+      {{- if $debug }}
+      log2(mem, state_last_mem, debug_state_event, debug_phase_compute_alpha_square_lagrange_0)
+      {{- end }}
+      {{- if $profile }}
+      log2(0, 0, profile_enter_event, profile_section_verify_quotient_poly_eval_at_zeta)
+      {{- end }}
       verify_quotient_poly_eval_at_zeta(proof)
+      {{- if $profile }}
+      log2(0, 0, profile_exit_event, profile_section_verify_quotient_poly_eval_at_zeta)
+      {{- end }}
+      {{- if $debug }}
+      log2(mem, state_last_mem, debug_state_event, debug_phase_verify_quotient_poly_eval_at_zeta)
+      {{- end }}
+      {{- if $profile }}
+      log2(0, 0, profile_enter_event, profile_section_fold_h)
+      {{- end }}
       fold_h(proof)
+      {{- if $profile }}
+      log2(0, 0, profile_exit_event, profile_section_fold_h)
+      {{- end }}
+      {{- if $debug }}
+      log2(mem, state_last_mem, debug_state_event, debug_phase_fold_h)
+      {{- end }}
+      {{- if $profile }}
+      log2(0, 0, profile_enter_event, profile_section_compute_commitment_linearised_polynomial)
+      {{- end }}
       compute_commitment_linearised_polynomial(proof)
+      {{- if $profile }}
+      log2(0, 0, profile_exit_event, profile_section_compute_commitment_linearised_polynomial)
+      {{- end }}
+      {{- if $debug }}
+      log2(mem, state_last_mem, debug_state_event, debug_phase_compute_commitment_linearised_polynomial)
+      {{- end }}
+      {{- if $profile }}
+      log2(0, 0, profile_enter_event, profile_section_compute_gamma_kzg)
+      {{- end }}
       compute_gamma_kzg(proof)
+      {{- if $profile }}
+      log2(0, 0, profile_exit_event, profile_section_compute_gamma_kzg)
+      {{- end }}
+      {{- if $debug }}
+      log2(mem, state_last_mem, debug_state_event, debug_phase_compute_gamma_kzg)
+      {{- end }}
+      {{- if $profile }}
+      log2(0, 0, profile_enter_event, profile_section_fold_state)
+      {{- end }}
       fold_state(proof)
+      {{- if $profile }}
+      log2(0, 0, profile_exit_event, profile_section_fold_state)
+      {{- end }}
+      {{- if $debug }}
+      log2(mem, state_last_mem, debug_state_event, debug_phase_fold_state)
+      {{- end }}
+      {{- if $profile }}
+      log2(0, 0, profile_enter_event, profile_section_batch_verify_multi_points)
+      {{- end }}
       batch_verify_multi_points(proof)
-
-      success := mload(add(mem, state_success))
+      {{- if $profile }}
+      log2(0, 0, profile_exit_event, profile_section_batch_verify_multi_points)
+      {{- end }}
+      {{- if $debug }}
+      log2(mem, state_last_mem, debug_state_event, debug_phase_batch_verify_multi_points)
+      {{- end }}
+
+      {{- if $debug }}
       
       check := mload(add(mem, state_check_var))
+      {{- end }}
 
       // compute α² * 1/n * (ζ{n}-1)/(ζ - 1) where
       // * α = challenge derived in derive_gamma_beta_alpha_zeta
@@ -572,7 +777,9 @@ library PlonkVerifier {
         mstore(folded_evals_commit, 0x1)
         mstore(add(folded_evals_commit, 0x20), 0x2)
         mstore(add(folded_evals_commit, 0x40), mload(folded_evals))
-        pop(staticcall(sub(gas(), 2000),7,folded_evals_commit,0x60,folded_evals_commit,0x40))
+        if iszero(staticcall(sub(gas(), 2000),7,folded_evals_commit,0x60,folded_evals_commit,0x40)) {
+          revert_precompile_failed(7)
+        }
 
         let folded_evals_commit_y := add(folded_evals_commit, 0x20)
         mstore(folded_evals_commit_y, sub(p_mod, mload(folded_evals_commit_y)))
@@ -602,8 +809,13 @@ library PlonkVerifier {
         mstore(add(mPtr, 0x120), g2_srs_1_x_1)
         mstore(add(mPtr, 0x140), g2_srs_1_y_0)
         mstore(add(mPtr, 0x160), g2_srs_1_y_1)
-        let l_success := staticcall(sub(gas(), 2000),8,mPtr,0x180,0x00,0x20)
-        mstore(add(state, state_success), and(l_success,mload(add(state, state_success))))
+        if iszero(staticcall(sub(gas(), 2000),8,mPtr,0x180,0x00,0x20)) {
+          revert_precompile_failed(8)
+        }
+        if iszero(eq(mload(0x00), 1)) {
+          mstore(0x00, error_pairing_check_failed)
+          revert(0x00, 0x04)
+        }
       }
 
       // Fold the opening proofs at ζ:
@@ -627,7 +839,9 @@ library PlonkVerifier {
 
         point_acc_mul(add(state, state_folded_digests_x), add(mPtr,0x80), acc_gamma, mPtrOffset)
         fr_acc_mul(add(state, state_folded_claimed_values), add(aproof, proof_linearised_polynomial_at_zeta), acc_gamma)
+        {{- if $debug }}
         mstore(add(state, state_check_var), acc_gamma)
+        {{- end }}
         
         acc_gamma := mulmod(acc_gamma, l_gamma_kzg, r_mod)
         point_acc_mul(add(state, state_folded_digests_x), add(mPtr,0xc0), acc_gamma, mPtrOffset)
@@ -727,7 +941,9 @@ library PlonkVerifier {
         let start_input := 0x1b // 00.."gamma"
         let size_input := add(0x16, mul(vk_nb_commitments_commit_api,3)) // number of 32bytes elmts = 0x16 (zeta+2*7+7 for the digests+openings) + 2*vk_nb_commitments_commit_api (for the commitments of the selectors) + vk_nb_commitments_commit_api (for the openings of the selectors)
         size_input := add(0x5, mul(size_input, 0x20)) // size in bytes: 15*32 bytes + 5 bytes for gamma
-        pop(staticcall(sub(gas(), 2000), 0x2, add(mPtr,start_input), size_input, add(state, state_gamma_kzg), 0x20))
+        if iszero(staticcall(sub(gas(), 2000), 0x2, add(mPtr,start_input), size_input, add(state, state_gamma_kzg), 0x20)) {
+          revert_precompile_failed(2)
+        }
         mstore(add(state, state_gamma_kzg), mod(mload(add(state, state_gamma_kzg)), r_mod))
       }
 
@@ -885,41 +1101,46 @@ library PlonkVerifier {
         mstore(computed_quotient, addmod(mload(computed_quotient), sub(r_mod,mload(add(state, state_alpha_square_lagrange))), r_mod))
         mstore(s2, mulmod(mload(add(aproof,proof_quotient_polynomial_at_zeta)), mload(add(state, state_zeta_power_n_minus_one)), r_mod))
 
-        mstore(add(state, state_success),eq(mload(computed_quotient), mload(s2)))
+        if iszero(eq(mload(computed_quotient), mload(s2))) {
+          mstore(0x00, error_quotient_check_failed)
+          revert(0x00, 0x04)
+        }
       }
 
       function point_add(dst, p, q, mPtr) {
         // let mPtr := add(mload(0x40), state_last_mem)
-        let state := mload(0x40)
         mstore(mPtr, mload(p))
         mstore(add(mPtr, 0x20), mload(add(p, 0x20)))
         mstore(add(mPtr, 0x40), mload(q))
         mstore(add(mPtr, 0x60), mload(add(q, 0x20)))
-        let l_success := staticcall(sub(gas(), 2000),6,mPtr,0x80,dst,0x40)
-        mstore(add(state, state_success), and(l_success,mload(add(state, state_success))))
+        if iszero(staticcall(sub(gas(), 2000),6,mPtr,0x80,dst,0x40)) {
+          revert_precompile_failed(6)
+        }
       }
 
       // dst <- [s]src
       function point_mul(dst,src,s, mPtr) {
-        let state := mload(0x40)
         mstore(mPtr,mload(src))
         mstore(add(mPtr,0x20),mload(add(src,0x20)))
         mstore(add(mPtr,0x40),s)
-        let l_success := staticcall(sub(gas(), 2000),7,mPtr,0x60,dst,0x40)
-        mstore(add(state, state_success), and(l_success,mload(add(state, state_success))))
+        if iszero(staticcall(sub(gas(), 2000),7,mPtr,0x60,dst,0x40)) {
+          revert_precompile_failed(7)
+        }
       }
 
       // dst <- dst + [s]src (Elliptic curve)
       function point_acc_mul(dst,src,s, mPtr) {
-        let state := mload(0x40)
         mstore(mPtr,mload(src))
         mstore(add(mPtr,0x20),mload(add(src,0x20)))
         mstore(add(mPtr,0x40),s)
-        let l_success := staticcall(sub(gas(), 2000),7,mPtr,0x60,mPtr,0x40)
+        if iszero(staticcall(sub(gas(), 2000),7,mPtr,0x60,mPtr,0x40)) {
+          revert_precompile_failed(7)
+        }
         mstore(add(mPtr,0x40),mload(dst))
         mstore(add(mPtr,0x60),mload(add(dst,0x20)))
-        l_success := and(l_success, staticcall(sub(gas(), 2000),6,mPtr,0x80,dst, 0x40))
-        mstore(add(state, state_success), and(l_success,mload(add(state, state_success))))
+        if iszero(staticcall(sub(gas(), 2000),6,mPtr,0x80,dst, 0x40)) {
+          revert_precompile_failed(6)
+        }
       }
 
       // dst <- dst + src (Fr) dst,src are addresses, s is a value
@@ -936,12 +1157,24 @@ library PlonkVerifier {
         mstore(add(mPtr, 0x60), x)
         mstore(add(mPtr, 0x80), e)
         mstore(add(mPtr, 0xa0), r_mod)
-        pop(staticcall(sub(gas(), 2000),0x05,mPtr,0xc0,mPtr,0x20))
+        if iszero(staticcall(sub(gas(), 2000),0x05,mPtr,0xc0,mPtr,0x20)) {
+          revert_precompile_failed(5)
+        }
         res := mload(mPtr)
       }
+
+      function revert_precompile_failed(precompile) {
+        mstore(0x00, error_precompile_failed)
+        mstore(0x04, precompile)
+        revert(0x00, 0x24)
+      }
     }
+    {{- if $profile }}
+
+    assembly { log2(0, 0, profile_exit_event, profile_section_Verify) }
+    {{- end }}
 
-    return success;
+    return true;
 
   }
 
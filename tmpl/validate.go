@@ -0,0 +1,115 @@
+package tmpl
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+)
+
+// ErrInvalidVerifyingKey is wrapped by the errors of ValidateVerifyingKey.
+var ErrInvalidVerifyingKey = errors.New("invalid verifying key")
+
+// cosetShift is the shift of the cosets of the permutation, hardcoded in
+// Verifier.sol as vk_coset_shift
+const cosetShift = 5
+
+// ValidateVerifyingKey checks that vk is consistent, and that it is the key the
+// template assumes: a verifier rendered from an inconsistent key may reject every
+// proof, or accept forged ones. It checks that
+//   - Size is a power of two, SizeInv its inverse, and Generator of order Size
+//   - the public inputs fit in the domain
+//   - the commitments to the selectors and to the permutation are points of G1
+//   - the G2 points of the SRS are in G2, and its G1 point on the curve
+//   - there is a Qcp per BSB22 commitment, whose constraint indexes increase and
+//     fit in the domain
+//   - CosetShift is vk_coset_shift
+//
+// The error of the first failed check wraps ErrInvalidVerifyingKey.
+func ValidateVerifyingKey(vk *bn254plonk.VerifyingKey) error {
+
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidVerifyingKey, fmt.Sprintf(format, args...))
+	}
+
+	// domain
+	if vk.Size == 0 || vk.Size&(vk.Size-1) != 0 {
+		return invalid("Size %d is not a power of two", vk.Size)
+	}
+	var size, one fr.Element
+	size.SetUint64(vk.Size)
+	one.SetOne()
+	var product fr.Element
+	product.Mul(&vk.SizeInv, &size)
+	if !product.Equal(&one) {
+		return invalid("SizeInv * Size = %s, expected 1", product.String())
+	}
+	// ω of order n: ωⁿ = 1 and, n being a power of two, ωⁿᐟ² ≠ 1
+	var power fr.Element
+	power.Exp(vk.Generator, new(big.Int).SetUint64(vk.Size))
+	if !power.Equal(&one) {
+		return invalid("Generator^Size = %s, expected 1", power.String())
+	}
+	if vk.Size > 1 {
+		power.Exp(vk.Generator, new(big.Int).SetUint64(vk.Size/2))
+		if power.Equal(&one) {
+			return invalid("Generator %s has an order smaller than Size %d", vk.Generator.String(), vk.Size)
+		}
+	}
+	if vk.NbPublicVariables >= vk.Size {
+		return invalid("NbPublicVariables %d doesn't fit in a domain of size %d", vk.NbPublicVariables, vk.Size)
+	}
+
+	// commitments of the selectors and of the permutation
+	commitments := []struct {
+		name  string
+		point *bn254.G1Affine
+	}{
+		{"Ql", &vk.Ql}, {"Qr", &vk.Qr}, {"Qm", &vk.Qm}, {"Qo", &vk.Qo}, {"Qk", &vk.Qk},
+		{"S[0]", &vk.S[0]}, {"S[1]", &vk.S[1]}, {"S[2]", &vk.S[2]},
+		{"Kzg.G1", &vk.Kzg.G1},
+	}
+	for i := range vk.Qcp {
+		commitments = append(commitments, struct {
+			name  string
+			point *bn254.G1Affine
+		}{fmt.Sprintf("Qcp[%d]", i), &vk.Qcp[i]})
+	}
+	for _, c := range commitments {
+		if !c.point.IsOnCurve() || !c.point.IsInSubGroup() {
+			return invalid("%s = %s is not a point of G1", c.name, c.point.String())
+		}
+	}
+
+	// SRS
+	for i := range vk.Kzg.G2 {
+		if !vk.Kzg.G2[i].IsOnCurve() || !vk.Kzg.G2[i].IsInSubGroup() {
+			return invalid("Kzg.G2[%d] = %s is not a point of G2", i, vk.Kzg.G2[i].String())
+		}
+	}
+
+	// BSB22 commitments
+	if len(vk.CommitmentConstraintIndexes) != len(vk.Qcp) {
+		return invalid("%d CommitmentConstraintIndexes for %d Qcp", len(vk.CommitmentConstraintIndexes), len(vk.Qcp))
+	}
+	for i, index := range vk.CommitmentConstraintIndexes {
+		if i > 0 && index <= vk.CommitmentConstraintIndexes[i-1] {
+			return invalid("CommitmentConstraintIndexes[%d] = %d doesn't follow %d", i, index, vk.CommitmentConstraintIndexes[i-1])
+		}
+		// Verifier.sol evaluates the Lagrange polynomial at index+nb_public_inputs
+		if index+vk.NbPublicVariables >= vk.Size {
+			return invalid("CommitmentConstraintIndexes[%d] = %d is out of a domain of size %d with %d public variables", i, index, vk.Size, vk.NbPublicVariables)
+		}
+	}
+
+	var shift fr.Element
+	shift.SetUint64(cosetShift)
+	if !vk.CosetShift.Equal(&shift) {
+		return invalid("CosetShift is %s, the template assumes %d", vk.CosetShift.String(), cosetShift)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package tmpl
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	bn254plonk "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/plonk-solidity/internal/circuits"
+)
+
+// copyVerifyingKey returns a copy of vk which doesn't share its slices
+func copyVerifyingKey(vk *bn254plonk.VerifyingKey) *bn254plonk.VerifyingKey {
+	res := *vk
+	res.Qcp = append([]bn254.G1Affine{}, vk.Qcp...)
+	res.CommitmentConstraintIndexes = append([]uint64{}, vk.CommitmentConstraintIndexes...)
+	return &res
+}
+
+// offCurve returns (x, y+1), which is not on Bn254 if (x, y) is. The cofactor of
+// G1 is 1, a point of Bn254 out of G1 doesn't exist.
+func offCurve(p bn254.G1Affine) bn254.G1Affine {
+	var one fp.Element
+	one.SetOne()
+	p.Y.Add(&p.Y, &one)
+	return p
+}
+
+// TestValidateVerifyingKey corrupts one field of a valid verifying key per case,
+// and checks that the key is rejected with an error naming the field, and that
+// no verifier is rendered from it.
+func TestValidateVerifyingKey(t *testing.T) {
+
+	// a key with several BSB22 commitments
+	example, err := circuits.Get("multiple-commitments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setup, err := example.Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vk := setup.VK
+	if len(vk.Qcp) < 2 {
+		t.Fatalf("%d commitments, expected at least 2", len(vk.Qcp))
+	}
+	if err := ValidateVerifyingKey(vk); err != nil {
+		t.Fatalf("the valid key is rejected: %v", err)
+	}
+
+	// a point of the twist curve, out of G2 since its cofactor is not cleared
+	var u bn254.G2Affine
+	u.X.SetString("1", "2")
+	notInG2 := bn254.MapToCurve2(&u.X)
+	if !notInG2.IsOnCurve() || notInG2.IsInSubGroup() {
+		t.Fatal("the point mapped to the twist curve is in G2")
+	}
+
+	testCases := []struct {
+		name    string
+		field   string
+		corrupt func(vk *bn254plonk.VerifyingKey)
+	}{
+		{"Size not a power of two", "Size", func(vk *bn254plonk.VerifyingKey) {
+			vk.Size += 1
+		}},
+		{"wrong SizeInv", "SizeInv", func(vk *bn254plonk.VerifyingKey) {
+			vk.SizeInv.Double(&vk.SizeInv)
+		}},
+		{"Generator of order 2·Size", "Generator", func(vk *bn254plonk.VerifyingKey) {
+			// ω is a square since the 2-adicity of r-1 is 28 > log n
+			vk.Generator.Sqrt(&vk.Generator)
+		}},
+		{"Generator of order Size/2", "Generator", func(vk *bn254plonk.VerifyingKey) {
+			vk.Generator.Square(&vk.Generator)
+		}},
+		{"NbPublicVariables = Size", "NbPublicVariables", func(vk *bn254plonk.VerifyingKey) {
+			vk.NbPublicVariables = vk.Size
+		}},
+		{"S[1] off the curve", "S[1]", func(vk *bn254plonk.VerifyingKey) {
+			vk.S[1] = offCurve(vk.S[1])
+		}},
+		{"Ql off the curve", "Ql", func(vk *bn254plonk.VerifyingKey) {
+			vk.Ql = offCurve(vk.Ql)
+		}},
+		{"Qcp[1] off the curve", "Qcp[1]", func(vk *bn254plonk.VerifyingKey) {
+			vk.Qcp[1] = offCurve(vk.Qcp[1])
+		}},
+		{"Kzg.G2[1] out of G2", "Kzg.G2[1]", func(vk *bn254plonk.VerifyingKey) {
+			vk.Kzg.G2[1] = notInG2
+		}},
+		{"missing Qcp", "Qcp", func(vk *bn254plonk.VerifyingKey) {
+			vk.Qcp = vk.Qcp[:len(vk.Qcp)-1]
+		}},
+		{"extra commitment constraint index", "CommitmentConstraintIndexes", func(vk *bn254plonk.VerifyingKey) {
+			vk.CommitmentConstraintIndexes = append(vk.CommitmentConstraintIndexes, vk.Size-1)
+		}},
+		{"non increasing indexes", "CommitmentConstraintIndexes[1]", func(vk *bn254plonk.VerifyingKey) {
+			vk.CommitmentConstraintIndexes[1] = vk.CommitmentConstraintIndexes[0]
+		}},
+		{"index out of the domain", "CommitmentConstraintIndexes[1]", func(vk *bn254plonk.VerifyingKey) {
+			vk.CommitmentConstraintIndexes = vk.CommitmentConstraintIndexes[:2]
+			vk.CommitmentConstraintIndexes[1] = vk.Size - vk.NbPublicVariables
+			vk.Qcp = vk.Qcp[:2]
+		}},
+		{"CosetShift is not 5", "CosetShift", func(vk *bn254plonk.VerifyingKey) {
+			vk.CosetShift.SetUint64(7)
+		}},
+	}
+
+	for _, tc := range testCases {
+		corrupted := copyVerifyingKey(vk)
+		tc.corrupt(corrupted)
+
+		err := ValidateVerifyingKey(corrupted)
+		if !errors.Is(err, ErrInvalidVerifyingKey) {
+			t.Errorf("%s: expected ErrInvalidVerifyingKey, got %v", tc.name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.field) {
+			t.Errorf("%s: the error doesn't name %s: %v", tc.name, tc.field, err)
+		}
+
+		folder := t.TempDir()
+		err = GenerateVerifierWithOptions(*corrupted, bn254plonk.Proof{}, nil, folder, Options{AllowTestSRS: true}, nil)
+		if !errors.Is(err, ErrInvalidVerifyingKey) {
+			t.Errorf("%s: GenerateVerifierWithOptions: expected ErrInvalidVerifyingKey, got %v", tc.name, err)
+		}
+		if entries, _ := os.ReadDir(folder); len(entries) != 0 {
+			t.Errorf("%s: %d files written", tc.name, len(entries))
+		}
+	}
+}